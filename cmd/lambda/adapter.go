@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pkg/errors"
+)
+
+/*
+Adapter turns API Gateway HTTP API (payload version 2.0) events into requests for an http.Handler and its response
+back into the event API Gateway expects, so the Lambda serves exactly what cmd/server does. The handler is built once
+and reused by every invocation while the Lambda stays warm.
+*/
+type Adapter struct {
+	handler http.Handler
+}
+
+func NewAdapter(handler http.Handler) *Adapter {
+	return &Adapter{handler: handler}
+}
+
+func (a *Adapter) Handle(ctx context.Context, event events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	request, err := newRequest(ctx, event)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{}, err
+	}
+
+	recorder := httptest.NewRecorder()
+	a.handler.ServeHTTP(recorder, request)
+	return newResponse(recorder), nil
+}
+
+func newRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (*http.Request, error) {
+	body := event.Body
+	if event.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding the body of request [%s]", event.RequestContext.RequestID)
+		}
+		body = string(decoded)
+	}
+
+	// named stages are part of the path, the handler only knows the routes beneath them
+	path := event.RawPath
+	if stage := event.RequestContext.Stage; stage != "" && stage != "$default" {
+		path = strings.TrimPrefix(path, "/"+stage)
+	}
+	if event.RawQueryString != "" {
+		path += "?" + event.RawQueryString
+	}
+
+	request, err := http.NewRequestWithContext(ctx, event.RequestContext.HTTP.Method, path, strings.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating request [%s]", event.RequestContext.RequestID)
+	}
+
+	for name, value := range event.Headers {
+		request.Header.Set(name, value)
+	}
+	if len(event.Cookies) > 0 {
+		request.Header.Set("Cookie", strings.Join(event.Cookies, "; "))
+	}
+	request.Host = event.RequestContext.DomainName
+	request.RemoteAddr = event.RequestContext.HTTP.SourceIP
+	request.RequestURI = path
+	return request, nil
+}
+
+func newResponse(recorder *httptest.ResponseRecorder) events.APIGatewayV2HTTPResponse {
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: recorder.Code,
+		Headers:    map[string]string{},
+	}
+	// payload version 2.0 has no multi value headers, API Gateway expects them joined by commas
+	for name, values := range recorder.Header() {
+		response.Headers[name] = strings.Join(values, ",")
+	}
+
+	if body := recorder.Body.Bytes(); utf8.Valid(body) {
+		response.Body = string(body)
+	} else {
+		response.Body = base64.StdEncoding.EncodeToString(body)
+		response.IsBase64Encoded = true
+	}
+	return response
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAdapter(t *testing.T) *Adapter {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	handler, err := newHandler(*log, Config{
+		AnswersFile: filepath.Join("testdata", "answers.txt"),
+		Strategy:    "entropy",
+		Timeout:     time.Second,
+	})
+	require.NoError(t, err)
+	return NewAdapter(handler)
+}
+
+// recordedEvent reads an API Gateway event from testdata
+func recordedEvent(t *testing.T, name string) events.APIGatewayV2HTTPRequest {
+	contents, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	var event events.APIGatewayV2HTTPRequest
+	require.NoError(t, json.Unmarshal(contents, &event))
+	return event
+}
+
+func TestAdapterSolves(t *testing.T) {
+	response, err := testAdapter(t).Handle(context.Background(), recordedEvent(t, "solve.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/json", response.Headers["Content-Type"])
+	assert.False(t, response.IsBase64Encoded)
+
+	var body server.SolveResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &body))
+	assert.Equal(t, 2, body.Count)
+	assert.Equal(t, []string{"crane", "crate"}, body.Candidates)
+	assert.Len(t, body.Suggestions, 1)
+}
+
+func TestAdapterDecodesBase64AndStripsTheStage(t *testing.T) {
+	response, err := testAdapter(t).Handle(context.Background(), recordedEvent(t, "solve-base64-stage.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode, response.Body)
+	var body server.SolveResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &body))
+	assert.Equal(t, []string{"crate"}, body.Candidates)
+	require.NotEmpty(t, body.Suggestions)
+	assert.Equal(t, server.Suggestion{Word: "crate", ExpectedRemaining: 1, WorstCase: 1, Candidate: true}, body.Suggestions[0])
+}
+
+func TestAdapterReturnsTheHandlersErrors(t *testing.T) {
+	adapter := testAdapter(t)
+
+	response, err := adapter.Handle(context.Background(), recordedEvent(t, "invalid-feedback.json"))
+	require.NoError(t, err, "invalid requests are responses, not invocation errors")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.JSONEq(t, `{"error": "unexpected [R] at position 4 in feedback [BGBRB], expected G, Y and B, 2, 1 and 0 or the share emoji", "field": "guesses[0].feedback"}`, response.Body)
+
+	event := recordedEvent(t, "solve.json")
+	event.RequestContext.HTTP.Method = http.MethodGet
+	response, err = adapter.Handle(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	assert.Equal(t, http.MethodPost, response.Headers["Allow"])
+
+	event = recordedEvent(t, "solve.json")
+	event.IsBase64Encoded = true
+	_, err = adapter.Handle(context.Background(), event)
+	assert.Error(t, err)
+}
+
+func TestNewHandlerNeedsTheWordLists(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	_, err = newHandler(*log, Config{AnswersFile: filepath.Join("testdata", "missing.txt"), Strategy: "entropy"})
+	assert.Error(t, err)
+
+	_, err = newHandler(*log, Config{AnswersFile: filepath.Join("testdata", "answers.txt"), Strategy: "guesswork"})
+	assert.Error(t, err)
+}
+
+// the lists make build-lambda packages, named as serverless.yaml names them
+func TestNewHandlerLoadsThePackagedWordLists(t *testing.T) {
+	if _, err := os.Stat(filepath.Join("..", "search", "answers.txt")); os.IsNotExist(err) {
+		t.Skip("cmd/search/answers.txt has not been built by tools/dictionary")
+	}
+
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	_, err = newHandler(*log, Config{
+		AnswersFile: filepath.Join("..", "search", "answers.txt"),
+		GuessesFile: filepath.Join("..", "search", "dictionary.txt"),
+		Strategy:    "entropy",
+		Timeout:     time.Second,
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/go-logr/logr"
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/wordgen"
+	"github.com/howzat/wordle/server"
+	"github.com/sethvargo/go-envconfig"
+)
+
+var CommitID string
+
+// Config is read from the environment of the function, the word lists are packaged next to the bootstrap binary
+type Config struct {
+	AnswersFile   string        `env:"ANSWERS_FILE,default=answers.txt"`
+	GuessesFile   string        `env:"GUESSES_FILE"`
+	FrequencyFile string        `env:"FREQUENCY_FILE"`
+	Strategy      string        `env:"STRATEGY,default=entropy"`
+	Timeout       time.Duration `env:"TIMEOUT,default=10s"`
+}
+
+/*
+The index is loaded once when the Lambda starts and shared by every invocation the runtime sends to this process, only
+a cold start pays for reading the word lists.
+*/
+func main() {
+	log, err := wordle.NewProductionLogger("wordle-lambda")
+	failOnErr(err)
+
+	var config Config
+	failOnErr(envconfig.Process(context.Background(), &config))
+
+	handler, err := newHandler(*log, config)
+	failOnErr(err)
+
+	log.Info("started", "commitId", CommitID, "answers", config.AnswersFile, "guesses", config.GuessesFile)
+	lambda.Start(NewAdapter(handler).Handle)
+}
+
+func newHandler(log logr.Logger, config Config) (http.Handler, error) {
+	strategy, err := db.StrategyNamed(config.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(config.AnswersFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	var guesses []string
+	if config.GuessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(config.GuessesFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	index, err := db.NewIndexWithAnswers(log, guesses, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.FrequencyFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(config.FrequencyFile, wordgen.NormaliseWord, wordgen.NoFilter())
+		if err != nil {
+			return nil, err
+		}
+		index.SetPriors(frequencies)
+	}
+
+	return server.New(log, index, db.NewSolver(index, strategy), config.Timeout).Handler(), nil
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -1,4 +1,123 @@
-package search
+package main
 
-type SearchConfig struct {
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/tui"
+	"github.com/howzat/wordle/internal/wordgen"
+	"golang.org/x/term"
+)
+
+var CommitID string
+
+func main() {
+
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of possible answers")
+	guessesFile := flag.String("guesses", "", "line separated list of additional allowed guesses")
+	frequenciesFile := flag.String("frequencies", "", "tab separated word counts built by tools/dictionary, used to order candidates")
+	strategyName := flag.String("strategy", db.EntropyStrategyName, fmt.Sprintf("how to suggest the next guess, one of %v", db.StrategyNames()))
+	length := flag.Int("length", db.DefaultWordLength, "how many letters the wordle has")
+	hardMode := flag.Bool("hard", false, "only accept and suggest guesses that are allowed in hard mode")
+	tuiMode := flag.String("tui", "", "draw the board full screen instead of the REPL, play to guess a hidden word or assist to type the feedback from another game")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("wordle-search")
+	failOnErr(err)
+
+	strategy, err := db.StrategyNamed(*strategyName)
+	failOnErr(err)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	if *frequenciesFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(*frequenciesFile, wordgen.NormaliseWord, wordgen.NoFilter())
+		failOnErr(err)
+		index.SetPriors(frequencies)
+	}
+
+	log.Info("loaded dictionary",
+		"commitId", CommitID,
+		"answers", *answersFile,
+		"guesses", *guessesFile,
+		"words", len(index.Words(*length)),
+		"strategy", strategy.Name(),
+	)
+
+	solver := db.NewSolver(index, strategy)
+	if *tuiMode != "" {
+		mode, err := tui.ModeNamed(*tuiMode)
+		failOnErr(err)
+		failOnErr(runTUI(mode, index, solver, *length))
+		return
+	}
+
+	failOnErr(run(NewREPL(index, solver, *length, *hardMode)))
+}
+
+// run reads commands with line editing and history when stdin is a terminal, otherwise line by line so that a
+// session can be piped in
+func run(repl *REPL) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		repl.out = os.Stdout
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if !repl.Handle(scanner.Text()) {
+				return nil
+			}
+		}
+		return scanner.Err()
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "wordle> ")
+	repl.out = terminal
+
+	_, _ = fmt.Fprintln(terminal, "type help for a list of commands")
+	for {
+		line, err := terminal.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !repl.Handle(line) {
+			return nil
+		}
+	}
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+var errQuit = errors.New("quit")
+
+const (
+	defaultCandidates  = 20
+	defaultSuggestions = 5
+)
+
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(r *REPL, args []string) error
+}
+
+// commands is a function rather than a var as help refers back to the list
+func commands() []command {
+	return []command{
+		{"guess", "guess <word> <feedback>", "add a guess and the feedback it got, as GYBBG, 21002 or 🟩🟨⬛⬛🟩", (*REPL).guess},
+		{"candidates", "candidates [n]", "list the words that are still possible, most likely first", (*REPL).candidates},
+		{"suggest", "suggest [n]", "recommend the next guess", (*REPL).suggest},
+		{"query", "query <terms>", "search the dictionary with a query such as s?a?e +r -tiou r!2", (*REPL).query},
+		{"undo", "undo", "remove the last guess", (*REPL).undo},
+		{"reset", "reset [length]", "start a new session, optionally for a different word length", (*REPL).reset},
+		{"save", "save <file>", "write the guesses made so far to a file", (*REPL).save},
+		{"load", "load <file>", "replace the session with the guesses in a file", (*REPL).load},
+		{"help", "help", "show this message", (*REPL).help},
+		{"quit", "quit", "leave, as does exit or ctrl-d", (*REPL).quit},
+	}
+}
+
+// REPL solves a wordle a command at a time, keeping the guesses made so far in a session
+type REPL struct {
+	index    *db.Index
+	solver   *db.Solver
+	session  *db.Session
+	hardMode bool
+	out      io.Writer
+}
+
+func NewREPL(index *db.Index, solver *db.Solver, length int, hardMode bool) *REPL {
+	return &REPL{
+		index:    index,
+		solver:   solver,
+		session:  db.NewSession(index, length),
+		hardMode: hardMode,
+		out:      os.Stdout,
+	}
+}
+
+// Handle runs the line and reports any error to the user, returning false once the user has asked to quit
+func (r *REPL) Handle(line string) bool {
+	err := r.Execute(line)
+	if err == errQuit {
+		return false
+	}
+	if err != nil {
+		r.printf("error: %v\n", err)
+	}
+	return true
+}
+
+func (r *REPL) Execute(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	name := strings.ToLower(fields[0])
+	if name == "exit" {
+		name = "quit"
+	}
+
+	for _, c := range commands() {
+		if c.name == name {
+			return c.run(r, fields[1:])
+		}
+	}
+	return errors.Errorf("unknown command [%s], type help for a list of commands", fields[0])
+}
+
+func (r *REPL) guess(args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: guess <word> <feedback>")
+	}
+
+	w, err := db.ParseGuessFeedback(args[0], strings.Join(args[1:], ""))
+	if err != nil {
+		return err
+	}
+
+	if r.hardMode {
+		if err := r.session.ValidateHardMode(w.Letters()); err != nil {
+			return err
+		}
+	}
+
+	if err := r.session.Add(*w); err != nil {
+		return err
+	}
+
+	if r.session.Solved() {
+		r.printf("solved in %d guesses\n", len(r.session.Guesses()))
+		return nil
+	}
+	r.printCandidates(10)
+	return nil
+}
+
+func (r *REPL) candidates(args []string) error {
+	n, err := count(args, defaultCandidates)
+	if err != nil {
+		return err
+	}
+
+	r.printCandidates(n)
+	return nil
+}
+
+func (r *REPL) printCandidates(n int) {
+	r.printWords(r.index.OrderByLikelihood(r.index.Filter(r.session.Constraints())), n)
+}
+
+func (r *REPL) suggest(args []string) error {
+	n, err := count(args, defaultSuggestions)
+	if err != nil {
+		return err
+	}
+
+	var suggestions []db.Suggestion
+	if r.hardMode {
+		suggestions, err = r.solver.SuggestHardMode(r.session.Constraints(), n)
+	} else {
+		suggestions, err = r.solver.Suggest(r.session.Constraints(), n)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, s := range suggestions {
+		candidate := ""
+		if s.Candidate {
+			candidate = "  could be the answer"
+		}
+		r.printf("%s  %.2f bits  %.1f expected  %d worst%s\n", s.Word, s.ExpectedBits, s.ExpectedRemaining, s.WorstCase, candidate)
+	}
+	return nil
+}
+
+func (r *REPL) query(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: query <terms>")
+	}
+
+	words, err := r.index.SearchQuery(strings.Join(args, " "))
+	if queryErr, ok := err.(db.QueryError); ok {
+		r.printf("%s\n", queryErr.Pointer())
+	}
+	if err != nil {
+		return err
+	}
+
+	r.printWords(words, defaultCandidates)
+	return nil
+}
+
+func (r *REPL) undo(_ []string) error {
+	undone, err := r.session.Undo()
+	if err != nil {
+		return err
+	}
+
+	r.printf("removed %s %s\n", undone.Letters(), undone.Feedback(db.LetterFeedback))
+	return nil
+}
+
+func (r *REPL) reset(args []string) error {
+	length := r.session.Length()
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > db.MaxWordLength {
+			return errors.Errorf("length [%s] must be a number between 1 and %d", args[0], db.MaxWordLength)
+		}
+		length = n
+	}
+
+	r.session = db.NewSession(r.index, length)
+	r.printf("new session for %d letter words\n", length)
+	return nil
+}
+
+// save writes one guess per line followed by its feedback, the same form the guess command takes
+func (r *REPL) save(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: save <file>")
+	}
+
+	var builder strings.Builder
+	for _, w := range r.session.Guesses() {
+		builder.WriteString(w.Letters() + " " + w.Feedback(db.LetterFeedback) + "\n")
+	}
+
+	if err := os.WriteFile(args[0], []byte(builder.String()), 0644); err != nil {
+		return errors.Wrapf(err, "error saving session to [%s]", args[0])
+	}
+
+	r.printf("saved %d guesses to %s\n", len(r.session.Guesses()), args[0])
+	return nil
+}
+
+// load only replaces the session once every line of the file has been read
+func (r *REPL) load(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: load <file>")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return errors.Wrapf(err, "error loading session from [%s]", args[0])
+	}
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(file)
+
+	var session *db.Session
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return errors.Errorf("line %d of [%s] must be a guess followed by its feedback", n, args[0])
+		}
+
+		w, err := db.ParseGuessFeedback(fields[0], strings.Join(fields[1:], ""))
+		if err != nil {
+			return errors.Wrapf(err, "line %d of [%s]", n, args[0])
+		}
+
+		if session == nil {
+			session = db.NewSession(r.index, w.Length())
+		}
+		if err := session.Add(*w); err != nil {
+			return errors.Wrapf(err, "line %d of [%s]", n, args[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "error loading session from [%s]", args[0])
+	}
+
+	if session == nil {
+		session = db.NewSession(r.index, r.session.Length())
+	}
+	r.session = session
+	r.printf("loaded %d guesses from %s\n", len(session.Guesses()), args[0])
+	return nil
+}
+
+func (r *REPL) help(_ []string) error {
+	for _, c := range commands() {
+		r.printf("  %-24s %s\n", c.usage, c.help)
+	}
+	return nil
+}
+
+func (r *REPL) quit(_ []string) error {
+	return errQuit
+}
+
+func (r *REPL) printWords(words []string, n int) {
+	r.printf("%d candidates\n", len(words))
+	if n > 0 && len(words) > n {
+		r.printf("%s ...\n", strings.Join(words[:n], " "))
+	} else if len(words) > 0 {
+		r.printf("%s\n", strings.Join(words, " "))
+	}
+}
+
+func (r *REPL) printf(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(r.out, format, a...)
+}
+
+func count(args []string, fallback int) (int, error) {
+	if len(args) == 0 {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return 0, errors.Errorf("[%s] is not a count", args[0])
+	}
+	return n, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestREPL(t *testing.T, hardMode bool) (*REPL, *bytes.Buffer) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	index, err := db.NewIndex(*log, []string{"brick", "crane", "crate", "flock", "plumb", "slate", "trace"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	repl := NewREPL(index, db.NewSolver(index, db.EntropyStrategy{}), db.DefaultWordLength, hardMode)
+	repl.out = &out
+	return repl, &out
+}
+
+func TestREPLSolvesAPuzzle(t *testing.T) {
+	repl, out := newTestREPL(t, false)
+
+	require.NoError(t, repl.Execute("guess slate BBGGG"))
+	assert.Equal(t, "1 candidates\ncrate\n", out.String())
+
+	out.Reset()
+	require.NoError(t, repl.Execute("suggest 1"))
+	assert.Contains(t, out.String(), "crate")
+	assert.Contains(t, out.String(), "could be the answer")
+
+	out.Reset()
+	require.NoError(t, repl.Execute("guess CRATE 🟩🟩🟩🟩🟩"))
+	assert.Equal(t, "solved in 2 guesses\n", out.String())
+
+	assert.EqualError(t, repl.Execute("guess brick BBBBB"), "session is already solved")
+
+	out.Reset()
+	require.NoError(t, repl.Execute("undo"))
+	assert.Equal(t, "removed crate GGGGG\n", out.String())
+
+	out.Reset()
+	require.NoError(t, repl.Execute("reset"))
+	require.NoError(t, repl.Execute("candidates 3"))
+	assert.Equal(t, "new session for 5 letter words\n7 candidates\nbrick crane crate ...\n", out.String())
+}
+
+func TestREPLReportsErrors(t *testing.T) {
+	repl, out := newTestREPL(t, true)
+
+	assert.EqualError(t, repl.Execute("guess slate"), "usage: guess <word> <feedback>")
+	assert.EqualError(t, repl.Execute("guess slate GGR"), "unexpected [R] at position 3 in feedback [GGR], expected G, Y and B, 2, 1 and 0 or the share emoji")
+	assert.EqualError(t, repl.Execute("candidates lots"), "[lots] is not a count")
+	assert.EqualError(t, repl.Execute("reset 99"), "length [99] must be a number between 1 and 20")
+	assert.EqualError(t, repl.Execute("dance"), "unknown command [dance], type help for a list of commands")
+
+	require.NoError(t, repl.Execute("guess slate BBGGG"))
+	assert.EqualError(t, repl.Execute("guess brick BBBBB"), "3rd letter must be A, it was green in guess 1 [slate]")
+
+	out.Reset()
+	assert.Error(t, repl.Execute("query s?a#e"))
+	assert.Equal(t, "s?a#e\n   ^\n", out.String())
+
+	out.Reset()
+	assert.True(t, repl.Handle("dance"))
+	assert.Equal(t, "error: unknown command [dance], type help for a list of commands\n", out.String())
+	assert.False(t, repl.Handle("exit"))
+	assert.True(t, repl.Handle(""))
+}
+
+func TestREPLSavesAndLoadsSessions(t *testing.T) {
+	repl, _ := newTestREPL(t, false)
+	file := filepath.Join(t.TempDir(), "session.txt")
+
+	require.NoError(t, repl.Execute("guess slate BBGGG"))
+	require.NoError(t, repl.Execute("save "+file))
+
+	contents, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "slate BBGGG\n", string(contents))
+
+	require.NoError(t, repl.Execute("reset"))
+	require.NoError(t, repl.Execute("load "+file))
+	assert.Len(t, repl.session.Guesses(), 1)
+	assert.Equal(t, "slate", repl.session.Guesses()[0].Letters())
+
+	require.NoError(t, os.WriteFile(file, []byte("slate BBGGG\ncrate\n"), 0644))
+	assert.EqualError(t, repl.Execute("load "+file), "line 2 of ["+file+"] must be a guess followed by its feedback")
+	assert.Len(t, repl.session.Guesses(), 1, "a bad file leaves the session as it was")
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"os"
+)
+
+// notifyResize never signals, there is no SIGWINCH so the TUI keeps the size it started with
+func notifyResize() <-chan os.Signal {
+	return nil
+}
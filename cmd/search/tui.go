@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/tui"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// runTUI draws the board full screen until the player quits, redrawing whenever the terminal is resized
+func runTUI(mode tui.Mode, index *db.Index, solver *db.Solver, length int) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("the TUI needs a terminal")
+	}
+
+	var ui *tui.TUI
+	if mode == tui.Play {
+		answers := index.Answers(length)
+		if len(answers) == 0 {
+			return errors.Errorf("the dictionary has no answers of length %d", length)
+		}
+
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		var err error
+		if ui, err = tui.NewPlay(os.Stdout, index, answers[random.Intn(len(answers))]); err != nil {
+			return err
+		}
+	} else {
+		ui = tui.NewAssist(os.Stdout, index, solver, length)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = os.Stdout.WriteString(tui.ShowCursor)
+		_ = term.Restore(fd, state)
+	}()
+
+	resize := func() error {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			return err
+		}
+		return ui.Resize(width, height)
+	}
+	if err := resize(); err != nil {
+		return err
+	}
+
+	keys := make(chan []byte)
+	go func() {
+		defer close(keys)
+		for {
+			buf := make([]byte, 64)
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			keys <- buf[:n]
+		}
+	}()
+
+	resized := notifyResize()
+	for {
+		select {
+		case <-resized:
+			if err := resize(); err != nil {
+				return err
+			}
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			more, err := ui.HandleInput(k)
+			if err != nil || !more {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/wordgen"
+	"github.com/howzat/wordle/server"
+)
+
+var CommitID string
+
+func main() {
+
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of possible answers")
+	guessesFile := flag.String("guesses", "", "line separated list of additional allowed guesses")
+	frequenciesFile := flag.String("frequencies", "", "tab separated word counts built by tools/dictionary, used to order candidates")
+	strategyName := flag.String("strategy", db.EntropyStrategyName, fmt.Sprintf("how to suggest the next guess, one of %v", db.StrategyNames()))
+	timeout := flag.Duration("timeout", server.DefaultTimeout, "how long a request can take before it is abandoned")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("wordle-server")
+	failOnErr(err)
+
+	strategy, err := db.StrategyNamed(*strategyName)
+	failOnErr(err)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	if *frequenciesFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(*frequenciesFile, wordgen.NormaliseWord, wordgen.NoFilter())
+		failOnErr(err)
+		index.SetPriors(frequencies)
+	}
+
+	s := server.New(*log, index, db.NewSolver(index, strategy), *timeout)
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      *timeout + 5*time.Second,
+	}
+
+	log.Info("listening",
+		"commitId", CommitID,
+		"addr", *addr,
+		"answers", *answersFile,
+		"guesses", *guessesFile,
+		"strategy", strategy.Name(),
+	)
+	failOnErr(httpServer.ListenAndServe())
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,60 @@
+package db
+
+import "math/bits"
+
+// bitset holds one bit per dense word id, a nil bitset is an empty set
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (b bitset) has(i int) bool {
+	return i/64 < len(b) && b[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+func (b bitset) clone(size int) bitset {
+	c := newBitset(size)
+	copy(c, b)
+	return c
+}
+
+func (b bitset) and(o bitset) {
+	for i := range b {
+		if i < len(o) {
+			b[i] &= o[i]
+		} else {
+			b[i] = 0
+		}
+	}
+}
+
+func (b bitset) andNot(o bitset) {
+	for i := range b {
+		if i < len(o) {
+			b[i] &^= o[i]
+		}
+	}
+}
+
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (b bitset) each(fn func(i int)) {
+	for wi, w := range b {
+		for w != 0 {
+			t := bits.TrailingZeros64(w)
+			fn(wi*64 + t)
+			w &= w - 1
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitsetOperations(t *testing.T) {
+	a := newBitset(130)
+	b := newBitset(130)
+	for _, i := range []int{0, 3, 64, 129} {
+		a.set(i)
+	}
+	for _, i := range []int{3, 64, 100} {
+		b.set(i)
+	}
+
+	assert.Equal(t, 4, a.count())
+	assert.True(t, a.has(129))
+	assert.False(t, a.has(130))
+
+	and := a.clone(130)
+	and.and(b)
+	assert.Equal(t, []int{3, 64}, members(and))
+
+	andNot := a.clone(130)
+	andNot.andNot(b)
+	assert.Equal(t, []int{0, 129}, members(andNot))
+
+	none := a.clone(130)
+	none.and(nil)
+	assert.Equal(t, 0, none.count())
+
+	all := a.clone(130)
+	all.andNot(nil)
+	assert.Equal(t, []int{0, 3, 64, 129}, members(all))
+
+	assert.Equal(t, 0, bitset(nil).clone(130).count())
+}
+
+func members(b bitset) []int {
+	var ids []int
+	b.each(func(i int) {
+		ids = append(ids, i)
+	})
+	return ids
+}
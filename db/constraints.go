@@ -0,0 +1,170 @@
+package db
+
+import (
+	"sort"
+)
+
+// Constraints is the combined knowledge of the wordle gathered from one or more guesses
+type Constraints struct {
+	length   int
+	greens   map[int]byte
+	minCount map[byte]int
+	maxCount map[byte]int
+	excluded map[int]map[byte]bool
+}
+
+func NewConstraints(length int) *Constraints {
+	return &Constraints{
+		length:   length,
+		greens:   map[int]byte{},
+		minCount: map[byte]int{},
+		maxCount: map[byte]int{},
+		excluded: map[int]map[byte]bool{},
+	}
+}
+
+/*
+Add merges a scored guess into the constraints. A letter marked Full or Present n times in one row means the
+wordle contains at least n of it, and if the same letter is also marked Absent in that row it contains exactly n.
+*/
+func (c *Constraints) Add(guess Wordle) {
+	found := map[byte]int{}
+	capped := map[byte]bool{}
+	for i, k := range guess.knowledge {
+		l := guess.letters[i]
+		switch k {
+		case Full:
+			c.greens[i] = l
+			found[l]++
+		case Present:
+			c.exclude(i, l)
+			found[l]++
+		case Absent:
+			c.exclude(i, l)
+			capped[l] = true
+		}
+	}
+
+	for l, n := range found {
+		if n > c.minCount[l] {
+			c.minCount[l] = n
+		}
+	}
+
+	for l := range capped {
+		if max, ok := c.maxCount[l]; !ok || found[l] < max {
+			c.maxCount[l] = found[l]
+		}
+	}
+}
+
+func (c *Constraints) exclude(pos int, l byte) {
+	if _, ok := c.excluded[pos]; !ok {
+		c.excluded[pos] = map[byte]bool{}
+	}
+	c.excluded[pos][l] = true
+}
+
+func (c *Constraints) Length() int {
+	return c.length
+}
+
+func (c *Constraints) Greens() map[int]string {
+	greens := make(map[int]string, len(c.greens))
+	for i, l := range c.greens {
+		greens[i] = string(l)
+	}
+	return greens
+}
+
+func (c *Constraints) RequiredLetters() []string {
+	required := map[byte]bool{}
+	for l, n := range c.minCount {
+		if n > 0 {
+			required[l] = true
+		}
+	}
+	return sortedLetters(required)
+}
+
+func (c *Constraints) AbsentLetters() []string {
+	absent := map[byte]bool{}
+	for l, n := range c.maxCount {
+		if n == 0 {
+			absent[l] = true
+		}
+	}
+	return sortedLetters(absent)
+}
+
+// MinCount is the fewest times the letter can appear in the wordle
+func (c *Constraints) MinCount(l string) int {
+	return c.minCount[l[0]]
+}
+
+// MaxCount is the most times the letter can appear in the wordle, or -1 when that is not yet known
+func (c *Constraints) MaxCount(l string) int {
+	if max, ok := c.maxCount[l[0]]; ok {
+		return max
+	}
+	return -1
+}
+
+func (c *Constraints) ExcludedAt(pos int) []string {
+	return sortedLetters(c.excluded[pos])
+}
+
+func (c *Constraints) Matches(word string) bool {
+	if len(word) != c.length {
+		return false
+	}
+
+	for i, l := range c.greens {
+		if word[i] != l {
+			return false
+		}
+	}
+
+	for i := 0; i < len(word); i++ {
+		if c.excluded[i][word[i]] {
+			return false
+		}
+	}
+
+	return c.satisfiesLetterCounts(word)
+}
+
+func (c *Constraints) satisfiesLetterCounts(word string) bool {
+	for l, min := range c.minCount {
+		if countByte(word, l) < min {
+			return false
+		}
+	}
+
+	for l, max := range c.maxCount {
+		if countByte(word, l) > max {
+			return false
+		}
+	}
+
+	return true
+}
+
+func countByte(word string, l byte) int {
+	n := 0
+	for i := 0; i < len(word); i++ {
+		if word[i] == l {
+			n++
+		}
+	}
+	return n
+}
+
+func sortedLetters(set map[byte]bool) []string {
+	var letters []string
+	for l := range set {
+		letters = append(letters, string(l))
+	}
+	sort.Strings(letters)
+	return letters
+}
@@ -10,11 +10,31 @@ type WordSearchEngine interface {
 	Search(guess Wordle) (*MatchResult, error)
 }
 
+// ErrNoKnowledge is returned by an engine asked to search with a guess that reveals nothing about the wordle
+var ErrNoKnowledge = errors.New("searching without search will match the entire dictionary")
+
 type Wordle struct {
 	letters   string
 	knowledge []Knowlege
 }
 
+func (w Wordle) Length() int {
+	return len(w.letters)
+}
+
+func (w Wordle) Letters() string {
+	return w.letters
+}
+
+func (w Wordle) Knowledge() []Knowlege {
+	return append([]Knowlege{}, w.knowledge...)
+}
+
+// HasKnowledge is false when the guess has no feedback for any of its letters
+func (w Wordle) HasKnowledge() bool {
+	return w.knowledge != nil && !reflect.DeepEqual(w.knowledge, NoKnowledgeOf(w.Length()))
+}
+
 func (w Wordle) FullyKnownLetters() []string {
 	return w.filterKnowledgeBy(func(knowledge Knowlege) bool {
 		return knowledge == Full
@@ -23,10 +43,29 @@ func (w Wordle) FullyKnownLetters() []string {
 
 func (w Wordle) KnownLetters() []string {
 	return w.filterKnowledgeBy(func(knowledge Knowlege) bool {
-		return knowledge != None
+		return knowledge == Full || knowledge == Present
 	})
 }
 
+// AbsentLetters are the letters the wordle does not contain at all. A repeated letter that is Absent in one position
+// but Full or Present in another is not included, it only caps how many times the letter appears.
+func (w Wordle) AbsentLetters() []string {
+	known := map[string]bool{}
+	for _, l := range w.KnownLetters() {
+		known[l] = true
+	}
+
+	var absent []string
+	for _, l := range w.filterKnowledgeBy(func(knowledge Knowlege) bool {
+		return knowledge == Absent
+	}) {
+		if !known[l] {
+			absent = append(absent, l)
+		}
+	}
+	return absent
+}
+
 func (w Wordle) filterKnowledgeBy(f func(knowledge Knowlege) bool) []string {
 	var known []string
 	for i, k := range w.knowledge {
@@ -42,7 +81,8 @@ type Knowlege int8
 const (
 	Full Knowlege = iota
 	Present
-	None
+	None   // nothing is known about the letter yet
+	Absent // the game told us the letter is not in the word
 )
 
 type MatchResult struct {
@@ -50,12 +90,17 @@ type MatchResult struct {
 	Guess Wordle
 }
 
+const (
+	DefaultWordLength = 5
+	MaxWordLength     = 20 // the longest word whose feedback still fits in a Pattern
+)
+
 func NewWordleSearch(letters string, knowledge []Knowlege) (*Wordle, error) {
-	if len(letters) > 5 {
-		return nil, errors.New("guesses must have exactly 5 characters")
+	if len(letters) == 0 || len(letters) > MaxWordLength {
+		return nil, errors.Errorf("guesses must have between 1 and %d characters", MaxWordLength)
 	}
-	if len(letters) > 5 {
-		return nil, errors.New("knowledge must have exactly 5 items")
+	if knowledge != nil && len(knowledge) != len(letters) {
+		return nil, errors.Errorf("knowledge must have exactly %d items", len(letters))
 	}
 
 	return &Wordle{
@@ -74,28 +119,22 @@ type LocalSearchEngine struct {
 	words *Index
 }
 
-var NoKnowledge = []Knowlege{None, None, None, None, None}
-
-func (ws *LocalSearchEngine) Search(guess Wordle) (*MatchResult, error) {
+var NoKnowledge = NoKnowledgeOf(DefaultWordLength)
 
-	if guess.knowledge == nil || reflect.DeepEqual(guess.knowledge, NoKnowledge) {
-		return nil, errors.New("searching without search will match the entire dictionary")
+func NoKnowledgeOf(length int) []Knowlege {
+	k := make([]Knowlege, length)
+	for i := range k {
+		k[i] = None
 	}
+	return k
+}
 
-	var results []string
-	for i, fact := range guess.knowledge {
-		if fact == Present {
-			l := guess.letters[i]
-			ids := ws.words.index[string(l)]
-			for _, id := range ids {
-				word := ws.words.reverseIndex[id]
-				results = append(results, word)
-			}
-		}
+// Search runs the guess through the index, rejecting a guess that carries no knowledge at all
+func (ws *LocalSearchEngine) Search(guess Wordle) (*MatchResult, error) {
+
+	if !guess.HasKnowledge() {
+		return nil, ErrNoKnowledge
 	}
 
-	return &MatchResult{
-		Items: results,
-		Guess: guess,
-	}, nil
+	return ws.words.Search(guess)
 }
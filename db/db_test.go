@@ -34,23 +34,46 @@ func TestLetterMatches(t *testing.T) {
 			knowledge: []Knowlege{None, None, None, None, None},
 			err:       errors.New("searching without search will match the entire dictionary"),
 		}, {
-			name:       "providing 1 part knowledge should return all words containing that letter",
+			name:       "providing 1 part knowledge should return all words containing that letter elsewhere",
 			search:     "blink",
 			knowledge:  []Knowlege{Present, None, None, None, None},
-			dictionary: []string{"beast", "crank", "dense", "sober"},
-			results:    []string{"beast", "sober"},
+			dictionary: []string{"beast", "crank", "dense", "rebus", "sober"},
+			results:    []string{"rebus", "sober"},
 		}, {
-			name:       "providing 1 part knowledge matching 2 words should return all words containing those letters",
+			name:       "a present letter is never in the position it was guessed in",
 			search:     "blink",
 			knowledge:  []Knowlege{Present, None, None, None, None},
 			dictionary: []string{"beast", "crank", "dense", "sober"},
-			results:    []string{"beast", "sober"},
+			results:    []string{"sober"},
 		}, {
-			name:       "providing 2 part knowledge should return all words containing those letters",
-			search:     "blink", // assume the wordle is [b][l][oom <--no knowledge]
+			name:       "providing 2 part knowledge should return the words containing both letters",
+			search:     "blink",
 			knowledge:  []Knowlege{Present, Present, None, None, None},
-			dictionary: []string{"beast", "crank", "dense", "slides"},
-			results:    []string{"beast", "slides"},
+			dictionary: []string{"beast", "blend", "crank", "dense", "slide", "table"},
+			results:    []string{"table"},
+		}, {
+			name:       "absent letters should exclude every word containing them",
+			search:     "blink",
+			knowledge:  []Knowlege{Present, Absent, None, None, None},
+			dictionary: []string{"beast", "blend", "crank", "sober"},
+			results:    []string{"sober"},
+		}, {
+			name:       "a single absent letter should shrink the dictionary",
+			search:     "blink",
+			knowledge:  []Knowlege{None, None, None, None, Absent},
+			dictionary: []string{"beast", "crank", "dense", "sober"},
+			results:    []string{"beast", "dense", "sober"},
+		}, {
+			name:       "only words of the same length as the guess should be returned",
+			search:     "bank",
+			knowledge:  []Knowlege{Present, None, None, Absent},
+			dictionary: []string{"abut", "beast", "blob", "crab", "kerb", "sober"},
+			results:    []string{"abut", "crab"},
+		}, {
+			name:      "knowledge must be provided for every letter",
+			search:    "blink",
+			knowledge: []Knowlege{Present, None, None, None},
+			err:       errors.New("knowledge must have exactly 5 items"),
 		},
 	}
 	for _, tt := range tests {
@@ -59,12 +82,15 @@ func TestLetterMatches(t *testing.T) {
 			log, err := wordle.NewProductionLogger(tt.name)
 			require.NoError(t, err)
 
-			db, err := NewIndex(*log, tt.dictionary, UseXXHashID)
+			db, err := NewIndex(*log, tt.dictionary)
 			require.NoError(t, err)
 
 			wordleDb := NewSearchEngine(db)
 			search, err := NewWordleSearch(tt.search, tt.knowledge)
-			require.NoError(t, err)
+			if err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
 
 			matchResult, err := wordleDb.Search(*search)
 			if tt.err != nil {
@@ -84,7 +110,7 @@ func TestLetterMatchProps(t *testing.T) {
 	words, err := loadWords("../cmd/search/dictionary.txt")
 	require.NoError(t, err)
 
-	db, err := NewIndex(*log, words, UseXXHashID)
+	db, err := NewIndex(*log, words)
 
 	for i := 0; i < 1000; i++ {
 		word := db.PickRandomWord()
@@ -96,6 +122,7 @@ func TestLetterMatchProps(t *testing.T) {
 		allKnownLetter := wdl.KnownLetters()
 		for _, result := range searchResults.Items {
 			mustContainAllKnownLetters(t, allKnownLetter, result)
+			mustNotContainAbsentLetters(t, wdl.AbsentLetters(), result)
 			if len(wdl.FullyKnownLetters()) > 0 {
 				mustPreserveFullLetterMatches(t, *wdl, result)
 			}
@@ -148,13 +175,37 @@ func mustContainAllKnownLetters(t *testing.T, chars []string, item string) {
 	}
 }
 
+func mustNotContainAbsentLetters(t *testing.T, chars []string, item string) {
+	t.Helper()
+	for _, ch := range chars {
+		assert.NotContains(t, item, ch)
+	}
+}
+
 func TestBuildKnowledge(t *testing.T) {
 	k := BuildKnowledgeForGuess("stick", "cider")
-	assert.Equal(t, []Knowlege{Present, Present, None, None, None}, k)
+	assert.Equal(t, []Knowlege{Present, Present, Absent, Absent, Absent}, k)
 
 	k1 := BuildKnowledgeForGuess("stick", "stick")
 	assert.Equal(t, []Knowlege{Full, Full, Full, Full, Full}, k1)
 
 	k2 := BuildKnowledgeForGuess("perch", "audio")
-	assert.Equal(t, []Knowlege{None, None, None, None, None}, k2)
+	assert.Equal(t, []Knowlege{Absent, Absent, Absent, Absent, Absent}, k2)
+
+	k3 := BuildKnowledgeForGuess("planet", "plants")
+	assert.Equal(t, []Knowlege{Full, Full, Full, Full, Present, Absent}, k3)
+}
+
+func TestBuildKnowledgeForRepeatedLetters(t *testing.T) {
+	k := BuildKnowledgeForGuess("those", "geese")
+	assert.Equal(t, []Knowlege{Absent, Absent, Absent, Full, Full}, k)
+
+	k1 := BuildKnowledgeForGuess("abbey", "babes")
+	assert.Equal(t, []Knowlege{Present, Present, Full, Full, Absent}, k1)
+
+	k2 := BuildKnowledgeForGuess("cable", "eerie")
+	assert.Equal(t, []Knowlege{Absent, Absent, Absent, Absent, Full}, k2)
+
+	k3 := BuildKnowledgeForGuess("speed", "eerie")
+	assert.Equal(t, []Knowlege{Present, Present, Absent, Absent, Absent}, k3)
 }
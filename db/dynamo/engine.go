@@ -0,0 +1,215 @@
+package dynamo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// posting is a word read from a partition
+type posting struct {
+	answer bool
+	prior  float64
+}
+
+// SearchEngine is a db.WordSearchEngine reading the partitions written by Load
+type SearchEngine struct {
+	client   Client
+	table    string
+	timeout  time.Duration
+	pageSize int32 // the most items read per query, 0 lets DynamoDB page at 1MB
+}
+
+func NewSearchEngine(client Client, table string, timeout time.Duration) *SearchEngine {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &SearchEngine{
+		client:  client,
+		table:   table,
+		timeout: timeout,
+	}
+}
+
+// Search rejects a guess without knowledge as db.LocalSearchEngine does, rather than reading every word of its length
+func (e *SearchEngine) Search(guess db.Wordle) (*db.MatchResult, error) {
+	if !guess.HasKnowledge() {
+		return nil, db.ErrNoKnowledge
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	constraints := db.NewConstraints(guess.Length())
+	constraints.Add(guess)
+
+	words, err := e.Filter(ctx, constraints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &db.MatchResult{
+		Items: words,
+		Guess: guess,
+	}, nil
+}
+
+/*
+Filter lists every answer matching the constraints, most likely first and then alphabetically as
+db.Index.OrderByLikelihood does. Every match is in the partition of each green and of the fewest times each letter
+appears, so only the smallest of them is read and its words are checked against the constraints. Without a green or
+a present letter that is the partition of every word of the length.
+*/
+func (e *SearchEngine) Filter(ctx context.Context, c *db.Constraints) ([]string, error) {
+	length := c.Length()
+
+	var include []string
+	for i, l := range c.Greens() {
+		include = append(include, positionKey(length, i, l[0]))
+	}
+	for _, l := range db.Alphabet {
+		if min := c.MinCount(l); min > 0 {
+			include = append(include, countKey(length, l[0], min))
+		}
+	}
+
+	smallest := lengthKey(length)
+	if len(include) > 0 {
+		sizes, err := e.readSizes(ctx, length)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(include) // the greens come from a map, so ties are broken the same way every time
+		smallest = include[0]
+		for _, key := range include[1:] {
+			if sizes[key] < sizes[smallest] {
+				smallest = key
+			}
+		}
+	}
+
+	matches := map[string]posting{}
+	err := e.query(ctx, smallest, []string{attrWord, attrAnswer, attrPrior}, func(item map[string]types.AttributeValue) error {
+		word, p, err := postingOf(item)
+		if err != nil {
+			return err
+		}
+		if p.answer && c.Matches(word) {
+			matches[word] = p
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]string, 0, len(matches))
+	for word := range matches {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		pi, pj := matches[words[i]].prior, matches[words[j]].prior
+		if pi != pj {
+			return pi > pj
+		}
+		return words[i] < words[j]
+	})
+	return words, nil
+}
+
+// readSizes counts the words in each partition of the length, a partition missing from the sizes is empty
+func (e *SearchEngine) readSizes(ctx context.Context, length int) (map[string]int, error) {
+	sizes := map[string]int{}
+	err := e.query(ctx, sizeKey(length), []string{attrWord, attrSize}, func(item map[string]types.AttributeValue) error {
+		key, ok := item[attrWord].(*types.AttributeValueMemberS)
+		if !ok {
+			return errors.Errorf("item has no partition in [%s]", attrWord)
+		}
+		size, ok := item[attrSize].(*types.AttributeValueMemberN)
+		if !ok {
+			return errors.Errorf("partition [%s] has no [%s]", key.Value, attrSize)
+		}
+
+		n, err := strconv.Atoi(size.Value)
+		if err != nil {
+			return errors.Wrapf(err, "partition [%s] has an invalid [%s]", key.Value, attrSize)
+		}
+		sizes[key.Value] = n
+		return nil
+	})
+	return sizes, err
+}
+
+// query reads every page of the partition, projecting the attributes, and hands each item to fn
+func (e *SearchEngine) query(ctx context.Context, key string, attributes []string, fn func(map[string]types.AttributeValue) error) error {
+	names := map[string]string{"#pk": attrPartition}
+	projection := make([]string, len(attributes))
+	for i, attr := range attributes {
+		names["#"+attr] = attr
+		projection[i] = "#" + attr
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                aws.String(e.table),
+		KeyConditionExpression:   aws.String("#pk = :pk"),
+		ProjectionExpression:     aws.String(strings.Join(projection, ", ")),
+		ExpressionAttributeNames: names,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: key},
+		},
+	}
+	if e.pageSize > 0 {
+		input.Limit = aws.Int32(e.pageSize)
+	}
+
+	for {
+		output, err := e.client.Query(ctx, input)
+		if err != nil {
+			return errors.Wrapf(err, "error reading partition [%s] of table [%s]", key, e.table)
+		}
+
+		for _, item := range output.Items {
+			if err := fn(item); err != nil {
+				return errors.Wrapf(err, "error reading partition [%s] of table [%s]", key, e.table)
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+func postingOf(item map[string]types.AttributeValue) (string, posting, error) {
+	word, ok := item[attrWord].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", posting{}, errors.Errorf("item has no word in [%s]", attrWord)
+	}
+	answer, ok := item[attrAnswer].(*types.AttributeValueMemberBOOL)
+	if !ok {
+		return "", posting{}, errors.Errorf("word [%s] has no [%s] flag", word.Value, attrAnswer)
+	}
+	prior, ok := item[attrPrior].(*types.AttributeValueMemberN)
+	if !ok {
+		return "", posting{}, errors.Errorf("word [%s] has no [%s]", word.Value, attrPrior)
+	}
+
+	p, err := strconv.ParseFloat(prior.Value, 64)
+	if err != nil {
+		return "", posting{}, errors.Wrapf(err, "word [%s] has an invalid [%s]", word.Value, attrPrior)
+	}
+	return word.Value, posting{answer: answer.Value, prior: p}, nil
+}
@@ -0,0 +1,233 @@
+package dynamo
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAnswers = []string{"abbey", "belle", "brick", "crane", "crate", "eerie", "geese", "llama", "slate", "trace", "bell", "tree"}
+	testGuesses = []string{"alley", "eagle", "lease", "sassy", "salet", "teeth", "ease", "tell"}
+)
+
+func testIndex(t *testing.T) *db.Index {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	index, err := db.NewIndexWithAnswers(*log, testGuesses, testAnswers)
+	require.NoError(t, err)
+	index.SetPriors(map[string]float64{"crate": 50, "slate": 50, "trace": 20, "geese": 3})
+	return index
+}
+
+/*
+testClient is the in-memory table, or a table in DynamoDB Local when DYNAMO_ENDPOINT and DYNAMO_TEST_TABLE are set.
+The test table is overwritten so it must not be the one holding the dictionary.
+*/
+func testClient(t *testing.T) (Client, string) {
+	endpoint, table := os.Getenv("DYNAMO_ENDPOINT"), os.Getenv("DYNAMO_TEST_TABLE")
+	if endpoint == "" || table == "" {
+		return newMemoryClient(), DefaultTable
+	}
+	client, err := NewClient(context.Background(), os.Getenv("AWS_REGION"), endpoint)
+	require.NoError(t, err)
+	return client, table
+}
+
+func TestSearchMatchesTheIndex(t *testing.T) {
+	index := testIndex(t)
+	client, table := testClient(t)
+	_, err := Load(context.Background(), client, table, index)
+	require.NoError(t, err)
+
+	engine := NewSearchEngine(client, table, time.Second)
+	engine.pageSize = 2
+
+	for _, length := range index.Lengths() {
+		for _, answer := range index.Answers(length) {
+			for _, guess := range index.Words(length) {
+				w, err := db.NewWordleSearch(guess, db.BuildKnowledgeForGuess(answer, guess))
+				require.NoError(t, err)
+
+				expected, err := index.Search(*w)
+				require.NoError(t, err)
+				actual, err := engine.Search(*w)
+				require.NoError(t, err)
+
+				assert.Equal(t, expected.Items, actual.Items, "guessing %s for %s", guess, answer)
+				assert.Contains(t, actual.Items, answer)
+			}
+		}
+	}
+}
+
+func TestFilterMatchesTheIndexForSeveralGuesses(t *testing.T) {
+	index := testIndex(t)
+	client, table := testClient(t)
+	_, err := Load(context.Background(), client, table, index)
+	require.NoError(t, err)
+
+	engine := NewSearchEngine(client, table, time.Second)
+
+	tests := []struct {
+		answer  string
+		guesses []string
+	}{
+		{"geese", []string{"eagle", "teeth"}},
+		{"belle", []string{"alley", "tell"}},
+		{"crate", []string{"salet", "trace"}},
+		{"llama", []string{"sassy", "alley"}},
+	}
+	for _, tt := range tests {
+		constraints := db.NewConstraints(len(tt.answer))
+		for _, guess := range tt.guesses {
+			w, err := db.NewWordleSearch(guess, db.BuildKnowledgeForGuess(tt.answer, guess))
+			require.NoError(t, err)
+			constraints.Add(*w)
+		}
+
+		actual, err := engine.Filter(context.Background(), constraints)
+		require.NoError(t, err)
+		assert.Equal(t, index.OrderByLikelihood(index.Filter(constraints)), actual, tt.answer)
+	}
+}
+
+func TestFilterReadsOnlyTheSmallestPartition(t *testing.T) {
+	index := testIndex(t)
+	client := newMemoryClient()
+	_, err := Load(context.Background(), client, DefaultTable, index)
+	require.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "2"}, client.tables[DefaultTable][sizeKey(5)][countKey(5, 'e', 3)][attrSize])
+
+	engine := NewSearchEngine(client, DefaultTable, time.Second)
+	tests := []struct {
+		answer  string
+		guess   string
+		queries int
+	}{
+		{"geese", "eagle", 2},
+		{"llama", "alley", 2},
+		{"geese", "brick", 1},
+	}
+	for _, tt := range tests {
+		constraints := db.NewConstraints(len(tt.answer))
+		w, err := db.NewWordleSearch(tt.guess, db.BuildKnowledgeForGuess(tt.answer, tt.guess))
+		require.NoError(t, err)
+		constraints.Add(*w)
+
+		client.queries = 0
+		actual, err := engine.Filter(context.Background(), constraints)
+		require.NoError(t, err)
+		assert.Equal(t, index.OrderByLikelihood(index.Filter(constraints)), actual, tt.guess)
+		assert.Equal(t, tt.queries, client.queries, "the sizes and one partition are read for %s", tt.guess)
+	}
+}
+
+func TestSearchWithoutKnowledgeIsAnError(t *testing.T) {
+	index := testIndex(t)
+	client := newMemoryClient()
+	_, err := Load(context.Background(), client, DefaultTable, index)
+	require.NoError(t, err)
+
+	w, err := db.NewWordleSearch("tell", db.NoKnowledgeOf(4))
+	require.NoError(t, err)
+
+	client.queries = 0
+	_, err = NewSearchEngine(client, DefaultTable, 0).Search(*w)
+	assert.ErrorIs(t, err, db.ErrNoKnowledge)
+	assert.Zero(t, client.queries)
+
+	_, err = db.NewSearchEngine(index).Search(*w)
+	assert.ErrorIs(t, err, db.ErrNoKnowledge, "the local engine rejects it too")
+}
+
+func TestLoadRetriesUnprocessedItems(t *testing.T) {
+	index := testIndex(t)
+	client := newMemoryClient()
+	client.throttle = 3
+
+	written, err := Load(context.Background(), client, DefaultTable, index)
+	require.NoError(t, err)
+
+	items := 0
+	for _, partition := range client.tables[DefaultTable] {
+		items += len(partition)
+	}
+	assert.Equal(t, items, written)
+	assert.Len(t, client.tables[DefaultTable][lengthKey(5)], 16)
+	assert.Len(t, client.tables[DefaultTable][countKey(5, 'e', 3)], 2, "eerie and geese")
+	assert.Len(t, client.tables[DefaultTable][positionKey(4, 0, 't')], 2, "tell and tree")
+}
+
+func TestLoadReplacesAnEarlierLoad(t *testing.T) {
+	client := newMemoryClient()
+	_, err := Load(context.Background(), client, DefaultTable, testIndex(t))
+	require.NoError(t, err)
+
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+	smaller, err := db.NewIndexWithAnswers(*log, []string{"salet"}, []string{"crane", "crate", "slate", "trace"})
+	require.NoError(t, err)
+
+	written, err := Load(context.Background(), client, DefaultTable, smaller)
+	require.NoError(t, err)
+
+	items := 0
+	for _, partition := range client.tables[DefaultTable] {
+		items += len(partition)
+	}
+	assert.Equal(t, written, items, "only the items of the last load are left")
+	assert.NotContains(t, client.tables[DefaultTable], lengthKey(4))
+	assert.NotContains(t, client.tables[DefaultTable][sizeKey(5)], countKey(5, 'e', 3), "geese and eerie were dropped")
+
+	constraints := db.NewConstraints(5)
+	w, err := db.NewWordleSearch("geese", db.BuildKnowledgeForGuess("crate", "geese"))
+	require.NoError(t, err)
+	constraints.Add(*w)
+
+	actual, err := NewSearchEngine(client, DefaultTable, time.Second).Filter(context.Background(), constraints)
+	require.NoError(t, err)
+	assert.Equal(t, smaller.Filter(constraints), actual)
+}
+
+func TestSearchReportsQueryErrors(t *testing.T) {
+	client := newMemoryClient()
+	client.queryError = errors.New("ResourceNotFoundException: table not found")
+
+	w, err := db.ParseGuessFeedback("crate", "GBYBB")
+	require.NoError(t, err)
+
+	_, err = NewSearchEngine(client, "missing", time.Second).Search(*w)
+	assert.ErrorContains(t, err, "of table [missing]: ResourceNotFoundException: table not found")
+}
+
+func TestPartitions(t *testing.T) {
+	assert.Equal(t, []string{
+		"len#5",
+		"pos#5#0#g", "count#5#g#1",
+		"pos#5#1#e", "count#5#e#1",
+		"pos#5#2#e", "count#5#e#2",
+		"pos#5#3#s", "count#5#s#1",
+		"pos#5#4#e", "count#5#e#3",
+	}, partitions("geese"))
+}
+
+func TestNewClientOnlyOverridesTheEndpointWhenGiven(t *testing.T) {
+	client, err := NewClient(context.Background(), "eu-west-2", "http://localhost:8000")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-2", client.Options().Region)
+	assert.Equal(t, "http://localhost:8000", *client.Options().BaseEndpoint)
+
+	client, err = NewClient(context.Background(), "eu-west-2", "")
+	require.NoError(t, err)
+	assert.Nil(t, client.Options().BaseEndpoint)
+}
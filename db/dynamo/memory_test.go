@@ -0,0 +1,125 @@
+package dynamo
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pkg/errors"
+)
+
+/*
+memoryClient is an in-memory table with just enough of DynamoDB for the package: queries on the partition key, paged
+by Limit and ExclusiveStartKey with the items in sort key order, scans returning the whole table in one page, and
+batch puts and deletes that can leave items unprocessed the way a throttled table does.
+*/
+type memoryClient struct {
+	mu         sync.Mutex
+	tables     map[string]map[string]map[string]map[string]types.AttributeValue // table, pk, sk
+	throttle   int                                                              // how many batch writes leave half their items unprocessed
+	queries    int
+	queryError error
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{tables: map[string]map[string]map[string]map[string]types.AttributeValue{}}
+}
+
+func (m *memoryClient) BatchWriteItem(_ context.Context, params *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unprocessed := map[string][]types.WriteRequest{}
+	for table, requests := range params.RequestItems {
+		if len(requests) > maxBatchWrite {
+			return nil, errors.Errorf("too many items in batch: %d", len(requests))
+		}
+		if m.throttle > 0 {
+			m.throttle--
+			unprocessed[table] = requests[len(requests)/2:]
+			requests = requests[:len(requests)/2]
+		}
+
+		if _, ok := m.tables[table]; !ok {
+			m.tables[table] = map[string]map[string]map[string]types.AttributeValue{}
+		}
+		for _, request := range requests {
+			if request.DeleteRequest != nil {
+				pk := request.DeleteRequest.Key[attrPartition].(*types.AttributeValueMemberS).Value
+				sk := request.DeleteRequest.Key[attrWord].(*types.AttributeValueMemberS).Value
+				delete(m.tables[table][pk], sk)
+				if len(m.tables[table][pk]) == 0 {
+					delete(m.tables[table], pk)
+				}
+				continue
+			}
+
+			item := request.PutRequest.Item
+			pk := item[attrPartition].(*types.AttributeValueMemberS).Value
+			sk := item[attrWord].(*types.AttributeValueMemberS).Value
+			if _, ok := m.tables[table][pk]; !ok {
+				m.tables[table][pk] = map[string]map[string]types.AttributeValue{}
+			}
+			m.tables[table][pk][sk] = item
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{UnprocessedItems: unprocessed}, nil
+}
+
+func (m *memoryClient) Query(_ context.Context, params *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queries++
+	if m.queryError != nil {
+		return nil, m.queryError
+	}
+	if *params.KeyConditionExpression != "#pk = :pk" || params.ExpressionAttributeNames["#pk"] != attrPartition {
+		return nil, errors.Errorf("unsupported key condition [%s]", *params.KeyConditionExpression)
+	}
+
+	partition := m.tables[*params.TableName][params.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS).Value]
+	words := make([]string, 0, len(partition))
+	for word := range partition {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	if start, ok := params.ExclusiveStartKey[attrWord]; ok {
+		after := start.(*types.AttributeValueMemberS).Value
+		words = words[sort.SearchStrings(words, after+"\x00"):]
+	}
+
+	output := &dynamodb.QueryOutput{}
+	if params.Limit != nil && int(*params.Limit) < len(words) {
+		words = words[:*params.Limit]
+		output.LastEvaluatedKey = map[string]types.AttributeValue{
+			attrPartition: params.ExpressionAttributeValues[":pk"],
+			attrWord:      &types.AttributeValueMemberS{Value: words[len(words)-1]},
+		}
+	}
+	for _, word := range words {
+		output.Items = append(output.Items, partition[word])
+	}
+	output.Count = int32(len(output.Items))
+	return output, nil
+}
+
+func (m *memoryClient) Scan(_ context.Context, params *dynamodb.ScanInput, _ ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	output := &dynamodb.ScanOutput{}
+	for pk, partition := range m.tables[*params.TableName] {
+		for sk := range partition {
+			output.Items = append(output.Items, map[string]types.AttributeValue{
+				attrPartition: &types.AttributeValueMemberS{Value: pk},
+				attrWord:      &types.AttributeValueMemberS{Value: sk},
+			})
+		}
+	}
+	output.Count = int32(len(output.Items))
+	return output, nil
+}
@@ -0,0 +1,242 @@
+/*
+Package dynamo searches for words held in a DynamoDB table instead of memory. The table keeps the same posting lists
+as db.Index keeps in bitsets, each partition lists the words sharing one property and every item is a word
+
+	pk                 sk     answer  prior
+	len#5              crate  true    1      every word of 5 letters
+	pos#5#0#c          crate  true    1      5 letter words with c as their 1st letter
+	count#5#e#1        crate  true    1      5 letter words with at least one e
+
+and the size#5 partition counts the words in each of them, with the partition as the sort key. A search reads the
+sizes and then only the smallest partition every match must be in, checking its words against the constraints in
+memory, so it finds exactly the words db.Index.Search finds.
+*/
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultTable = "words"
+
+	attrPartition = "pk"
+	attrWord      = "sk"
+	attrAnswer    = "answer"
+	attrPrior     = "prior"
+	attrSize      = "size"
+
+	maxBatchWrite = 25 // the most items DynamoDB takes in one BatchWriteItem
+	maxRetries    = 8
+)
+
+// Client is the part of the DynamoDB API the package uses, *dynamodb.Client implements it
+type Client interface {
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+}
+
+/*
+NewClient connects to DynamoDB in the region, or to DynamoDB Local when endpoint is set. Credentials come from the
+default chain, the environment that Lambda sets for the function's role, a shared profile or an instance role.
+*/
+func NewClient(ctx context.Context, region string, endpoint string) (*dynamodb.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading the AWS configuration")
+	}
+
+	return dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	}), nil
+}
+
+func lengthKey(length int) string {
+	return fmt.Sprintf("len#%d", length)
+}
+
+// sizeKey is the partition counting the words in every partition of the length
+func sizeKey(length int) string {
+	return fmt.Sprintf("size#%d", length)
+}
+
+func positionKey(length int, pos int, l byte) string {
+	return fmt.Sprintf("pos#%d#%d#%c", length, pos, l)
+}
+
+func countKey(length int, l byte, n int) string {
+	return fmt.Sprintf("count#%d#%c#%d", length, l, n)
+}
+
+// partitions lists every partition the word belongs to
+func partitions(word string) []string {
+	keys := []string{lengthKey(len(word))}
+	counts := map[byte]int{}
+	for i := 0; i < len(word); i++ {
+		keys = append(keys, positionKey(len(word), i, word[i]))
+		counts[word[i]]++
+		keys = append(keys, countKey(len(word), word[i], counts[word[i]]))
+	}
+	return keys
+}
+
+/*
+Load replaces the contents of the table with every word in the index and its partitions, marking the answers and
+keeping the priors so that searches order their results as the index does, followed by the size of each partition.
+Items left from an earlier load are deleted once the new ones are written, so a word dropped from the dictionary
+stops being found. It returns how many items were written.
+*/
+func Load(ctx context.Context, client Client, table string, index *db.Index) (int, error) {
+	puts := &batchWriter{ctx: ctx, client: client, table: table}
+	loaded := map[string]bool{}
+	put := func(item map[string]types.AttributeValue) error {
+		loaded[itemKey(item)] = true
+		return puts.add(types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+
+	for _, length := range index.Lengths() {
+		sizes := map[string]int{}
+		for _, word := range index.Words(length) {
+			for _, key := range partitions(word) {
+				sizes[key]++
+				err := put(map[string]types.AttributeValue{
+					attrPartition: &types.AttributeValueMemberS{Value: key},
+					attrWord:      &types.AttributeValueMemberS{Value: word},
+					attrAnswer:    &types.AttributeValueMemberBOOL{Value: index.IsAnswer(word)},
+					attrPrior:     &types.AttributeValueMemberN{Value: strconv.FormatFloat(index.Prior(word), 'g', -1, 64)},
+				})
+				if err != nil {
+					return puts.written, err
+				}
+			}
+		}
+
+		for key, size := range sizes {
+			err := put(map[string]types.AttributeValue{
+				attrPartition: &types.AttributeValueMemberS{Value: sizeKey(length)},
+				attrWord:      &types.AttributeValueMemberS{Value: key},
+				attrSize:      &types.AttributeValueMemberN{Value: strconv.Itoa(size)},
+			})
+			if err != nil {
+				return puts.written, err
+			}
+		}
+	}
+	if err := puts.flush(); err != nil {
+		return puts.written, err
+	}
+
+	return puts.written, deleteStale(ctx, client, table, loaded)
+}
+
+// deleteStale scans the keys of the table and deletes every item that was not loaded
+func deleteStale(ctx context.Context, client Client, table string, loaded map[string]bool) error {
+	var stale []map[string]types.AttributeValue
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(table),
+		ProjectionExpression:     aws.String("#pk, #sk"),
+		ExpressionAttributeNames: map[string]string{"#pk": attrPartition, "#sk": attrWord},
+	}
+	for {
+		output, err := client.Scan(ctx, input)
+		if err != nil {
+			return errors.Wrapf(err, "error scanning table [%s] for items left from an earlier load", table)
+		}
+		for _, item := range output.Items {
+			if !loaded[itemKey(item)] {
+				stale = append(stale, item)
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	deletes := &batchWriter{ctx: ctx, client: client, table: table}
+	for _, key := range stale {
+		if err := deletes.add(types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}}); err != nil {
+			return err
+		}
+	}
+	return deletes.flush()
+}
+
+// itemKey joins the partition and sort keys of an item
+func itemKey(item map[string]types.AttributeValue) string {
+	var pk, sk string
+	if v, ok := item[attrPartition].(*types.AttributeValueMemberS); ok {
+		pk = v.Value
+	}
+	if v, ok := item[attrWord].(*types.AttributeValueMemberS); ok {
+		sk = v.Value
+	}
+	return pk + "\x00" + sk
+}
+
+// batchWriter sends the write requests added to it in batches of the most DynamoDB takes at once
+type batchWriter struct {
+	ctx     context.Context
+	client  Client
+	table   string
+	batch   []types.WriteRequest
+	written int
+}
+
+func (b *batchWriter) add(request types.WriteRequest) error {
+	b.batch = append(b.batch, request)
+	if len(b.batch) < maxBatchWrite {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *batchWriter) flush() error {
+	if len(b.batch) == 0 {
+		return nil
+	}
+	if err := writeBatch(b.ctx, b.client, b.table, b.batch); err != nil {
+		return err
+	}
+	b.written += len(b.batch)
+	b.batch = b.batch[:0]
+	return nil
+}
+
+// writeBatch retries the items DynamoDB leaves unprocessed when the table is throttled, backing off each time
+func writeBatch(ctx context.Context, client Client, table string, batch []types.WriteRequest) error {
+	requests := map[string][]types.WriteRequest{table: batch}
+	backoff := 50 * time.Millisecond
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		output, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requests})
+		if err != nil {
+			return errors.Wrapf(err, "error writing %d items to table [%s]", len(requests[table]), table)
+		}
+		if len(output.UnprocessedItems[table]) == 0 {
+			return nil
+		}
+
+		requests = output.UnprocessedItems
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return errors.Errorf("%d items were still unprocessed after %d attempts to write to table [%s]", len(requests[table]), maxRetries, table)
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// FeedbackStyle is one of the textual forms players use to describe the feedback for a guess
+type FeedbackStyle int
+
+const (
+	LetterFeedback                 FeedbackStyle = iota // GYBBG
+	DigitFeedback                                       // 21002, the same digits as Pattern.Digits
+	EmojiFeedback                                       // 🟩🟨⬛⬛🟩 from the dark mode share sheet
+	LightEmojiFeedback                                  // 🟩🟨⬜⬜🟩 from the light mode share sheet
+	HighContrastEmojiFeedback                           // 🟧🟦⬛⬛🟧
+	HighContrastLightEmojiFeedback                      // 🟧🟦⬜⬜🟧
+)
+
+// UnknownFeedback marks a letter whose feedback is not known yet (None) in every style
+const UnknownFeedback = "?"
+
+var feedbackSymbols = map[FeedbackStyle][3]string{ // Full, Present, Absent
+	LetterFeedback:                 {"G", "Y", "B"},
+	DigitFeedback:                  {"2", "1", "0"},
+	EmojiFeedback:                  {"🟩", "🟨", "⬛"},
+	LightEmojiFeedback:             {"🟩", "🟨", "⬜"},
+	HighContrastEmojiFeedback:      {"🟧", "🟦", "⬛"},
+	HighContrastLightEmojiFeedback: {"🟧", "🟦", "⬜"},
+}
+
+var feedbackRunes = map[rune]Knowlege{
+	'G': Full, 'Y': Present, 'B': Absent, 'W': Absent, 'X': Absent,
+	'2': Full, '1': Present, '0': Absent,
+	'🟩': Full, '🟨': Present, '⬛': Absent, '⬜': Absent,
+	'🟧': Full, '🟦': Present,
+	'?': None,
+}
+
+const variationSelector = '\uFE0F' // some platforms follow ⬛ and ⬜ with it when sharing
+
+/*
+ParseFeedback reads the feedback for a guess in any of the styles, one symbol per letter. Letters are case
+insensitive, W or X may be used for Absent instead of B, ? marks a letter with no feedback and whitespace is ignored,
+so a row copied from the share sheet can be pasted as it is.
+*/
+func ParseFeedback(feedback string) ([]Knowlege, error) {
+	var knowledge []Knowlege
+	position := 0
+	for _, r := range feedback {
+		if unicode.IsSpace(r) || r == variationSelector {
+			continue
+		}
+		position++
+
+		k, ok := feedbackRunes[unicode.ToUpper(r)]
+		if !ok {
+			return nil, errors.Errorf("unexpected [%c] at position %d in feedback [%s], expected G, Y and B, 2, 1 and 0 or the share emoji",
+				r, position, feedback)
+		}
+		knowledge = append(knowledge, k)
+	}
+
+	if len(knowledge) == 0 {
+		return nil, errors.New("feedback must have at least one letter")
+	}
+	if len(knowledge) > MaxWordLength {
+		return nil, errors.Errorf("feedback [%s] is longer than %d letters", feedback, MaxWordLength)
+	}
+	return knowledge, nil
+}
+
+// ParseGuessFeedback pairs a guess with its feedback in any of the styles
+func ParseGuessFeedback(guess string, feedback string) (*Wordle, error) {
+	knowledge, err := ParseFeedback(feedback)
+	if err != nil {
+		return nil, err
+	}
+	if len(knowledge) != len(guess) {
+		return nil, errors.Errorf("feedback [%s] has %d letters but the guess [%s] has %d", feedback, len(knowledge), guess, len(guess))
+	}
+	return NewWordleSearch(strings.ToLower(guess), knowledge)
+}
+
+// FormatFeedback writes the feedback in the given style
+func FormatFeedback(knowledge []Knowlege, style FeedbackStyle) string {
+	symbols, ok := feedbackSymbols[style]
+	if !ok {
+		symbols = feedbackSymbols[LetterFeedback]
+	}
+
+	var builder strings.Builder
+	for _, k := range knowledge {
+		switch k {
+		case Full:
+			builder.WriteString(symbols[0])
+		case Present:
+			builder.WriteString(symbols[1])
+		case Absent:
+			builder.WriteString(symbols[2])
+		default:
+			builder.WriteString(UnknownFeedback)
+		}
+	}
+	return builder.String()
+}
+
+// Feedback writes the wordle's feedback in the given style
+func (w Wordle) Feedback(style FeedbackStyle) string {
+	return FormatFeedback(w.knowledge, style)
+}
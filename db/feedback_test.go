@@ -0,0 +1,90 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeedbackStyles(t *testing.T) {
+	expected := []Knowlege{Full, Present, Absent, Absent, Full}
+
+	for _, feedback := range []string{
+		"GYBBG",
+		"gybbg",
+		"GYWXG",
+		"21002",
+		"🟩🟨⬛⬛🟩",
+		"🟩🟨⬜⬜🟩",
+		"🟧🟦⬛⬛🟧",
+		"🟧🟦⬜⬜🟧",
+		"🟩🟨⬛️⬛️🟩",
+		" G Y B B G ",
+	} {
+		t.Run(feedback, func(t *testing.T) {
+			k, err := ParseFeedback(feedback)
+			require.NoError(t, err)
+			assert.Equal(t, expected, k)
+		})
+	}
+}
+
+func TestFeedbackRoundTrip(t *testing.T) {
+	styles := []FeedbackStyle{
+		LetterFeedback,
+		DigitFeedback,
+		EmojiFeedback,
+		LightEmojiFeedback,
+		HighContrastEmojiFeedback,
+		HighContrastLightEmojiFeedback,
+	}
+
+	for p := Pattern(0); int(p) < PatternCount(5); p++ {
+		k := p.Knowledge(5)
+		for _, style := range styles {
+			formatted := FormatFeedback(k, style)
+			parsed, err := ParseFeedback(formatted)
+			require.NoError(t, err, formatted)
+			assert.Equal(t, k, parsed, formatted)
+		}
+		assert.Equal(t, p.Digits(5), FormatFeedback(k, DigitFeedback))
+	}
+}
+
+func TestFormatFeedback(t *testing.T) {
+	k := []Knowlege{Full, Present, Absent, None, Full}
+
+	assert.Equal(t, "GYB?G", FormatFeedback(k, LetterFeedback))
+	assert.Equal(t, "210?2", FormatFeedback(k, DigitFeedback))
+	assert.Equal(t, "🟩🟨⬛?🟩", FormatFeedback(k, EmojiFeedback))
+	assert.Equal(t, "🟧🟦⬜?🟧", FormatFeedback(k, HighContrastLightEmojiFeedback))
+
+	parsed, err := ParseFeedback("GYB?G")
+	require.NoError(t, err)
+	assert.Equal(t, k, parsed)
+}
+
+func TestParseFeedbackErrors(t *testing.T) {
+	_, err := ParseFeedback("GYRBG")
+	assert.EqualError(t, err, "unexpected [R] at position 3 in feedback [GYRBG], expected G, Y and B, 2, 1 and 0 or the share emoji")
+
+	_, err = ParseFeedback("🟩🟨🟥")
+	assert.EqualError(t, err, "unexpected [🟥] at position 3 in feedback [🟩🟨🟥], expected G, Y and B, 2, 1 and 0 or the share emoji")
+
+	_, err = ParseFeedback("  ")
+	assert.EqualError(t, err, "feedback must have at least one letter")
+
+	_, err = ParseGuessFeedback("crane", "GYBB")
+	assert.EqualError(t, err, "feedback [GYBB] has 4 letters but the guess [crane] has 5")
+}
+
+func TestParseGuessFeedback(t *testing.T) {
+	w, err := ParseGuessFeedback("CRANE", "🟩🟨⬛⬛🟩")
+	require.NoError(t, err)
+
+	expected, err := NewWordleSearch("crane", []Knowlege{Full, Present, Absent, Absent, Full})
+	require.NoError(t, err)
+	assert.Equal(t, expected, w)
+	assert.Equal(t, "GYBBG", w.Feedback(LetterFeedback))
+}
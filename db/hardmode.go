@@ -0,0 +1,125 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HardModeError explains which hard mode rule a guess breaks and which earlier guess revealed the letter
+type HardModeError struct {
+	Guess    string
+	Letter   string
+	Position int // the position the letter must be reused in, or -1 when it may be anywhere
+	Count    int // how many times the letter must appear when it may be anywhere
+	Revealed Wordle
+	Row      int
+}
+
+func (e HardModeError) Error() string {
+	if e.Position >= 0 {
+		return fmt.Sprintf("%s letter must be %s, it was green in guess %d [%s]",
+			ordinal(e.Position+1), strings.ToUpper(e.Letter), e.Row+1, e.Revealed.letters)
+	}
+
+	letter := strings.ToUpper(e.Letter)
+	if e.Count > 1 {
+		return fmt.Sprintf("guess must contain %d %ss, they were revealed in guess %d [%s]",
+			e.Count, letter, e.Row+1, e.Revealed.letters)
+	}
+	return fmt.Sprintf("guess must contain %s, it was revealed in guess %d [%s]", letter, e.Row+1, e.Revealed.letters)
+}
+
+/*
+ValidateHardMode checks a guess against the hard mode rules for the guesses made so far: every green letter must be
+reused in the same position and every revealed letter must be used at least as many times as it was revealed.
+Greens are checked before yellows, both in the order they were revealed.
+*/
+func ValidateHardMode(guess string, history []Wordle) error {
+	for row, w := range history {
+		if len(guess) != w.Length() {
+			return errors.Errorf("guess [%s] must have %d letters", guess, w.Length())
+		}
+
+		for i, k := range w.knowledge {
+			if k == Full && guess[i] != w.letters[i] {
+				return HardModeError{
+					Guess:    guess,
+					Letter:   string(w.letters[i]),
+					Position: i,
+					Revealed: w,
+					Row:      row,
+				}
+			}
+		}
+	}
+
+	for row, w := range history {
+		revealed := map[byte]int{}
+		var order []byte
+		for i, k := range w.knowledge {
+			if k == Full || k == Present {
+				if revealed[w.letters[i]] == 0 {
+					order = append(order, w.letters[i])
+				}
+				revealed[w.letters[i]]++
+			}
+		}
+
+		for _, l := range order {
+			if countByte(guess, l) < revealed[l] {
+				return HardModeError{
+					Guess:    guess,
+					Letter:   string(l),
+					Position: -1,
+					Count:    revealed[l],
+					Revealed: w,
+					Row:      row,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) ValidateHardMode(guess string) error {
+	return ValidateHardMode(guess, s.guesses)
+}
+
+// AllowsInHardMode reports whether the word could be played in hard mode by whoever gathered the constraints
+func (c *Constraints) AllowsInHardMode(word string) bool {
+	if len(word) != c.length {
+		return false
+	}
+
+	for i, l := range c.greens {
+		if word[i] != l {
+			return false
+		}
+	}
+
+	for l, min := range c.minCount {
+		if countByte(word, l) < min {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ordinal(n int) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+		return fmt.Sprintf("%dth", n)
+	case n%10 == 1:
+		return fmt.Sprintf("%dst", n)
+	case n%10 == 2:
+		return fmt.Sprintf("%dnd", n)
+	case n%10 == 3:
+		return fmt.Sprintf("%drd", n)
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateHardMode(t *testing.T) {
+	history := []Wordle{
+		{letters: "crane", knowledge: []Knowlege{Absent, Full, Absent, Absent, Present}},
+		{letters: "greet", knowledge: []Knowlege{Absent, Full, Full, Present, Absent}},
+	}
+
+	tests := []struct {
+		guess string
+		err   string
+	}{
+		{guess: "freed"},
+		{guess: "breed"},
+		{guess: "fried", err: "3rd letter must be E, it was green in guess 2 [greet]"},
+		{guess: "agree", err: "2nd letter must be R, it was green in guess 1 [crane]"},
+		{guess: "prepd", err: "guess must contain 2 Es, they were revealed in guess 2 [greet]"},
+		{guess: "trees"},
+		{guess: "areas", err: "guess must contain 2 Es, they were revealed in guess 2 [greet]"},
+		{guess: "breeds", err: "guess [breeds] must have 5 letters"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateHardMode(tt.guess, history)
+		if tt.err == "" {
+			assert.NoError(t, err, tt.guess)
+		} else {
+			assert.EqualError(t, err, tt.err, tt.guess)
+		}
+	}
+
+	err := ValidateHardMode("brash", history[:1])
+	assert.EqualError(t, err, "guess must contain E, it was revealed in guess 1 [crane]")
+
+	var hardModeErr HardModeError
+	assert.ErrorAs(t, err, &hardModeErr)
+	assert.Equal(t, "e", hardModeErr.Letter)
+	assert.Equal(t, -1, hardModeErr.Position)
+}
+
+func TestSolverRestrictsSuggestionsToHardModeGuesses(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"batch", "catch", "hatch", "latch", "match", "patch", "watch", "clomp"})
+	require.NoError(t, err)
+
+	session := NewSession(db, 5)
+	guess, err := NewWordleSearch("latch", []Knowlege{Absent, Full, Full, Full, Full})
+	require.NoError(t, err)
+	require.NoError(t, session.Add(*guess))
+
+	solver := NewSolver(db, EntropyStrategy{})
+	suggestions, err := solver.SuggestHardMode(session.Constraints(), 0)
+	require.NoError(t, err)
+
+	for _, s := range suggestions {
+		assert.NotEqual(t, "clomp", s.Word)
+		assert.NoError(t, session.ValidateHardMode(s.Word))
+	}
+	assert.Len(t, suggestions, 7)
+
+	assert.Error(t, session.ValidateHardMode("clomp"))
+}
@@ -1,75 +1,200 @@
 package db
 
 import (
-	"hash"
 	"math/rand"
 	"sort"
 	"strings"
-	"time"
 
-	"github.com/cespare/xxhash"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 )
 
+/*
+Index numbers the words alphabetically so that every (length), (letter, position) and (letter, minimum count) can be
+held as a bitset and searches become a series of AND and AND NOT operations. The bitsets are large, so the index is
+only ever used through a pointer.
+*/
 type Index struct {
-	size         int
-	reverseIndex map[uint64]string
-	index        map[string][]uint64
+	words     []string
+	lengths   map[int]bitset
+	byLength  map[int][]int
+	positions [][256]bitset
+	minCounts [256][]bitset // minCounts[l][n] holds the words with more than n of the letter l
+	answers   bitset        // the words that can be the wordle, the rest are only valid guesses
+	priors    []float64     // how likely each word is to be the wordle, nil when every word is equally likely
 }
 
-type IDFn = func(string) (uint64, error)
-
-func NewIndex(_ logr.Logger, words []string, idFn IDFn) (*Index, error) {
-	index := map[string][]uint64{}
-	reverseIndex := make(map[uint64]string, len(words))
-	var recall = map[string]bool{}
+// NewIndex indexes the words in lower case, ignoring duplicates, and treats every one of them as a possible wordle
+func NewIndex(_ logr.Logger, words []string) (*Index, error) {
+	seen := make(map[string]bool, len(words))
+	d := &Index{words: make([]string, 0, len(words))}
 	for _, lw := range words {
 		w := strings.ToLower(lw)
-		if _, ok := recall[w]; !ok {
-			recall[w] = true
-		} else {
-			continue
+		if len(w) == 0 || len(w) > MaxWordLength {
+			return nil, errors.Errorf("word [%s] must have between 1 and %d letters", lw, MaxWordLength)
 		}
-
-		id, err := idFn(w)
-		if err != nil {
-			return nil, err
+		if !seen[w] {
+			seen[w] = true
+			d.words = append(d.words, w)
 		}
+	}
+	sort.Strings(d.words)
+	d.buildBitsets()
 
-		if a, ok := reverseIndex[id]; ok {
-			if a != w {
-				return nil, errors.Errorf("hash collision between %v and %v", a, w)
-			}
+	d.answers = newBitset(len(d.words))
+	for id := range d.words {
+		d.answers.set(id)
+	}
+	return d, nil
+}
+
+// NewIndexWithAnswers indexes every guess and answer but only treats the answers as possible wordles
+func NewIndexWithAnswers(log logr.Logger, guesses []string, answers []string) (*Index, error) {
+	d, err := NewIndex(log, append(append([]string{}, guesses...), answers...))
+	if err != nil {
+		return nil, err
+	}
+
+	d.answers = newBitset(len(d.words))
+	for _, a := range answers {
+		d.answers.set(d.id(strings.ToLower(a)))
+	}
+	return d, nil
+}
+
+func (d *Index) id(word string) int {
+	i := sort.SearchStrings(d.words, word)
+	if i < len(d.words) && d.words[i] == word {
+		return i
+	}
+	return -1
+}
+
+func (d *Index) Contains(word string) bool {
+	return d.id(word) >= 0
+}
+
+func (d *Index) IsAnswer(word string) bool {
+	id := d.id(word)
+	return id >= 0 && d.answers.has(id)
+}
+
+/*
+SetPriors weights every word by how often it is used, typically a count taken from a corpus. Each weight is
+smoothed by one so that words missing from the counts are unlikely rather than impossible.
+*/
+func (d *Index) SetPriors(counts map[string]float64) {
+	d.priors = make([]float64, len(d.words))
+	for id, w := range d.words {
+		d.priors[id] = counts[w] + 1
+	}
+}
+
+// Prior is the relative weight of the word being the wordle, 1 for every word until priors are set
+func (d *Index) Prior(word string) float64 {
+	id := d.id(word)
+	if id < 0 || d.priors == nil {
+		return 1
+	}
+	return d.priors[id]
+}
+
+// OrderByLikelihood sorts the words most likely first, falling back to alphabetical order
+func (d *Index) OrderByLikelihood(words []string) []string {
+	type weighted struct {
+		word  string
+		prior float64
+	}
+	// each lookup is a binary search so the priors are found once rather than on every comparison
+	byPrior := make([]weighted, len(words))
+	for i, w := range words {
+		byPrior[i] = weighted{w, d.Prior(w)}
+	}
+	sort.Slice(byPrior, func(i, j int) bool {
+		if byPrior[i].prior != byPrior[j].prior {
+			return byPrior[i].prior > byPrior[j].prior
 		}
+		return byPrior[i].word < byPrior[j].word
+	})
 
-		reverseIndex[id] = w
-		for _, c := range w {
-			index[string(c)] = append(index[string(c)], id)
+	ordered := make([]string, len(byPrior))
+	for i, w := range byPrior {
+		ordered[i] = w.word
+	}
+	return ordered
+}
+
+// Answers lists every word of the given length that can be the wordle alphabetically
+func (d *Index) Answers(length int) []string {
+	var answers []string
+	for _, id := range d.byLength[length] {
+		if d.answers.has(id) {
+			answers = append(answers, d.words[id])
 		}
 	}
+	return answers
+}
 
-	return &Index{
-		size:         len(reverseIndex),
-		reverseIndex: reverseIndex,
-		index:        index,
-	}, nil
+func (d *Index) buildBitsets() {
+	size := len(d.words)
+	d.lengths = map[int]bitset{}
+	d.byLength = map[int][]int{}
+	for id, w := range d.words {
+		if _, ok := d.lengths[len(w)]; !ok {
+			d.lengths[len(w)] = newBitset(size)
+		}
+		d.lengths[len(w)].set(id)
+		d.byLength[len(w)] = append(d.byLength[len(w)], id)
+
+		for len(d.positions) < len(w) {
+			d.positions = append(d.positions, [256]bitset{})
+		}
+
+		var counts [256]int
+		for i := 0; i < len(w); i++ {
+			l := w[i]
+			if d.positions[i][l] == nil {
+				d.positions[i][l] = newBitset(size)
+			}
+			d.positions[i][l].set(id)
+
+			if len(d.minCounts[l]) <= counts[l] {
+				d.minCounts[l] = append(d.minCounts[l], newBitset(size))
+			}
+			d.minCounts[l][counts[l]].set(id)
+			counts[l]++
+		}
+	}
 }
 
-func NewHashingIDFn(hr func() hash.Hash64) IDFn {
-	return func(s string) (uint64, error) {
-		h := hr()
-		_, err := h.Write([]byte(s))
-		return h.Sum64(), err
+// Lengths lists every word length held in the index, shortest first
+func (d *Index) Lengths() []int {
+	var lengths []int
+	for l := range d.lengths {
+		lengths = append(lengths, l)
 	}
+	sort.Ints(lengths)
+	return lengths
 }
 
-var UseXXHashID IDFn = NewHashingIDFn(xxhash.New)
+func (d *Index) SizeOfLength(length int) int {
+	return len(d.byLength[length])
+}
+
+// Words lists every valid guess of the given length alphabetically, answers included
+func (d *Index) Words(length int) []string {
+	words := make([]string, 0, len(d.byLength[length]))
+	for _, id := range d.byLength[length] {
+		words = append(words, d.words[id])
+	}
+	return words
+}
 
 var Alphabet = []string{"a",
 	"b",
 	"c",
 	"d",
+	"e",
 	"f",
 	"g",
 	"h",
@@ -93,73 +218,93 @@ var Alphabet = []string{"a",
 	"z",
 }
 
-func (d Index) PickRandomWord() string {
-	rand.Seed(time.Now().Unix())
-	firstAlpha := Alphabet[rand.Intn(25)]
-	ids := d.index[firstAlpha]
-	id := ids[rand.Intn(len(ids))]
-	return d.reverseIndex[id]
+// PickRandomWord picks any word in the index with equal probability, games that must repeat use the game package
+func (d *Index) PickRandomWord() string {
+	if len(d.words) == 0 {
+		return ""
+	}
+	return d.words[rand.Intn(len(d.words))]
 }
 
-func (d Index) Search(guess Wordle) (*MatchResult, error) {
-
-	letters := guess.KnownLetters()
-	var ids []uint64
-	for _, letter := range letters {
-		ids = append(ids, d.index[letter]...)
+func (d *Index) PickRandomWordOfLength(length int) string {
+	ids := d.byLength[length]
+	if len(ids) == 0 {
+		return ""
 	}
+	return d.words[ids[rand.Intn(len(ids))]]
+}
 
-	var memo = map[string]bool{}
-	var candidateResults []string
-	for _, id := range ids {
-		candidateWord := d.reverseIndex[id]
-		if _, ok := memo[candidateWord]; !ok {
-			memo[candidateWord] = true // we've processed this word before
-			if containsAllKnownLetters(letters, candidateWord) &&
-				fullyKnownLettersAreInCorrectPosition(guess, candidateWord) {
-				candidateResults = append(candidateResults, candidateWord)
-			}
-		} else {
-			continue
-		}
-	}
+func (d *Index) Search(guess Wordle) (*MatchResult, error) {
+
+	constraints := NewConstraints(guess.Length())
+	constraints.Add(guess)
 
-	sort.Strings(candidateResults)
 	return &MatchResult{
-		Items: candidateResults,
+		Items: d.OrderByLikelihood(d.Filter(constraints)),
 		Guess: guess,
 	}, nil
 }
 
-func fullyKnownLettersAreInCorrectPosition(wordle Wordle, letters string) bool {
-	if len(wordle.FullyKnownLetters()) == 0 {
-		return false
+// Filter lists every answer matching the constraints alphabetically
+func (d *Index) Filter(c *Constraints) []string {
+	var results []string
+	d.match(c).each(func(id int) {
+		results = append(results, d.words[id])
+	})
+	return results
+}
+
+func (d *Index) match(c *Constraints) bitset {
+	size := len(d.words)
+	result := d.lengths[c.length].clone(size)
+	result.and(d.answers)
+
+	for i, l := range c.greens {
+		result.and(d.position(i, l))
 	}
 
-	for i, k := range wordle.knowledge {
-		if k == Full {
-			if letters[i] != wordle.letters[i] {
-				return false
-			}
+	for l, min := range c.minCount {
+		if min > 0 {
+			result.and(d.minCount(l, min))
 		}
 	}
-	return true
-}
 
-func containsAllKnownLetters(letters []string, word string) bool {
-	for _, letter := range letters {
-		if !strings.Contains(word, letter) {
-			return false
+	for l, max := range c.maxCount {
+		result.andNot(d.minCount(l, max+1))
+	}
+
+	for i, excluded := range c.excluded {
+		for l := range excluded {
+			result.andNot(d.position(i, l))
 		}
 	}
 
-	return true
+	return result
+}
+
+func (d *Index) position(i int, l byte) bitset {
+	if i >= len(d.positions) {
+		return nil
+	}
+	return d.positions[i][l]
+}
+
+// minCount is the bitset of words containing the letter at least n times
+func (d *Index) minCount(l byte, n int) bitset {
+	if n > len(d.minCounts[l]) {
+		return nil
+	}
+	return d.minCounts[l][n-1]
 }
 
-func (d Index) CandidateGuess(wordle string) (*Wordle, error) {
+func (d *Index) CandidateGuess(wordle string) (*Wordle, error) {
+	if d.SizeOfLength(len(wordle)) == 0 {
+		return nil, errors.Errorf("the index has no words of length %d", len(wordle))
+	}
+
 	var candidateGuess string
 	for guess := ""; len(guess) == 0; guess = candidateGuess {
-		candidate := d.PickRandomWord()
+		candidate := d.PickRandomWordOfLength(len(wordle))
 		var knowledge = BuildKnowledgeForGuess(wordle, candidate)
 		search, err := NewWordleSearch(candidate, knowledge)
 		if err != nil {
@@ -173,28 +318,34 @@ func (d Index) CandidateGuess(wordle string) (*Wordle, error) {
 	return nil, nil
 }
 
+/*
+BuildKnowledgeForGuess scores a guess the way the official game does. Exact matches are marked Full first, then
+each remaining letter is marked Present only while the wordle still has unmatched copies of it, so a repeated letter
+in the guess is never reported more times than it appears in the wordle.
+*/
 func BuildKnowledgeForGuess(wordle string, guess string) []Knowlege {
 
-	wb := []byte(wordle)
-	var k = []Knowlege{None, None, None, None, None}
-	for i, char := range guess {
-		pos := findChar(wb, byte(char))
-		if pos >= 0 {
-			if pos == i {
-				k[i] = Full
-			} else {
-				k[i] = Present
-			}
+	var k = NoKnowledgeOf(len(guess))
+	unmatched := map[byte]int{}
+	for i := 0; i < len(guess); i++ {
+		if i < len(wordle) && wordle[i] == guess[i] {
+			k[i] = Full
+		} else if i < len(wordle) {
+			unmatched[wordle[i]]++
 		}
 	}
-	return k
-}
 
-func findChar(wordle []byte, char byte) int {
-	for i, b := range wordle {
-		if b == char {
-			return i
+	for i := 0; i < len(guess); i++ {
+		if k[i] == Full {
+			continue
+		}
+
+		if unmatched[guess[i]] > 0 {
+			unmatched[guess[i]]--
+			k[i] = Present
+		} else {
+			k[i] = Absent
 		}
 	}
-	return -1
+	return k
 }
@@ -2,9 +2,9 @@ package db
 
 import (
 	"fmt"
-	"hash"
 	"math/rand"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,88 +13,147 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestHashingIndexingWordDB(t *testing.T) {
+func TestIndexingWordDB(t *testing.T) {
 	log, err := wordle.NewProductionLogger(t.Name())
 	require.NoError(t, err)
 
-	db, err := NewIndex(*log, []string{"chunk", "latch", "LATCH", "Latch"}, UseXXHashID)
+	db, err := NewIndex(*log, []string{"chunk", "latch", "LATCH", "Latch"})
 	require.NoError(t, err)
 
-	assert.Equal(t, 2, len(db.reverseIndex))
-	assert.Equal(t, 8, len(db.index)) // c,h,u,n,k,l,a,t
+	assert.Equal(t, []string{"chunk", "latch"}, db.words)
+	assert.Equal(t, []string{"chunk", "latch"}, db.Words(5))
+
+	var letters []string
+	for _, l := range Alphabet {
+		if db.minCounts[l[0]] != nil && db.minCounts[l[0]][0].count() > 0 {
+			letters = append(letters, l)
+		}
+	}
+	assert.Equal(t, []string{"a", "c", "h", "k", "l", "n", "t", "u"}, letters)
 }
 
-func TestHashingIndexingCollisionsWordDB(t *testing.T) {
+// the ids are positions in the sorted words, so the same words give the same ids whatever order they are indexed in
+func TestIndexIDsAreConsistent(t *testing.T) {
 	log, err := wordle.NewProductionLogger(t.Name())
 	require.NoError(t, err)
 
-	_, err = NewIndex(*log, []string{"chunk", "latch", "LATCH", "Latch"}, UseFixedHasher)
-	assert.EqualError(t, err, "hash collision between chunk and latch")
-}
+	db, err := NewIndex(*log, []string{"chunk", "latch"})
+	require.NoError(t, err)
+	reordered, err := NewIndex(*log, []string{"Latch", "chunk", "latch"})
+	require.NoError(t, err)
 
-func TestHashingConsistencyForIndexedWordDB(t *testing.T) {
-	t.Run("test indexing with xxHash", testIndexingWithHasher(UseXXHashID))
-	t.Run("test indexing with seaHash", testIndexingWithHasher(UseXXHashID)) //TODO: use other hasher
-}
+	chunkID, latchID := db.id("chunk"), db.id("latch")
+	assert.Equal(t, 0, chunkID)
+	assert.Equal(t, 1, latchID)
+	assert.Equal(t, chunkID, reordered.id("chunk"))
+	assert.Equal(t, latchID, reordered.id("latch"))
+	assert.Equal(t, -1, db.id("crane"))
 
-func testIndexingWithHasher(id IDFn) func(b *testing.T) {
-	return func(t *testing.T) {
-		log, err := wordle.NewProductionLogger(t.Name())
-		require.NoError(t, err)
+	index := map[string][]int{}
+	for _, l := range Alphabet {
+		if db.minCounts[l[0]] != nil {
+			db.minCounts[l[0]][0].each(func(id int) {
+				index[l] = append(index[l], id)
+			})
+		}
+	}
+	assert.Equal(t, map[string][]int{
+		"a": {latchID},
+		"c": {chunkID, latchID},
+		"h": {chunkID, latchID},
+		"u": {chunkID},
+		"n": {chunkID},
+		"k": {chunkID},
+		"l": {latchID},
+		"t": {latchID},
+	}, index)
+	assert.Equal(t, "chunk", db.words[chunkID])
+	assert.Equal(t, "latch", db.words[latchID])
+}
 
-		db, err := NewIndex(*log, []string{"chunk", "latch"}, id)
-		require.NoError(t, err)
+// where a hashed id could collide, every distinct word gets its own id and looking it up gives the word back
+func TestIndexIDsNeverCollide(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
 
-		chunkID, err := id("chunk")
-		require.NoError(t, err)
+	rand.Seed(1)
+	words := []string{"latch", "chalt", "LATCH"}
+	for i := 0; i < 10000; i++ {
+		words = append(words, randomString())
+	}
 
-		latchID, err := id("latch")
-		require.NoError(t, err)
+	db, err := NewIndex(*log, words)
+	require.NoError(t, err)
 
-		index := map[string][]uint64{}
-		index["a"] = []uint64{latchID}
-		index["c"] = []uint64{chunkID, latchID}
-		index["h"] = []uint64{chunkID, latchID}
-		index["u"] = []uint64{chunkID}
-		index["n"] = []uint64{chunkID}
-		index["k"] = []uint64{chunkID}
-		index["l"] = []uint64{latchID}
-		index["t"] = []uint64{latchID}
-
-		assert.Equal(t, db.index, index)
-		assert.Equal(t, db.reverseIndex[chunkID], "chunk")
-		assert.Equal(t, db.reverseIndex[latchID], "latch")
+	ids := map[int]string{}
+	for _, w := range words {
+		word := strings.ToLower(w)
+		id := db.id(word)
+		require.GreaterOrEqual(t, id, 0, word)
+		if other, ok := ids[id]; ok {
+			assert.Equal(t, other, word, "id %d is shared by two words", id)
+		}
+		ids[id] = word
+		assert.Equal(t, word, db.words[id])
 	}
+	assert.Len(t, ids, len(db.words))
+	assert.NotEqual(t, db.id("latch"), db.id("chalt"))
 }
 
-// XXHashIndexedWordDB-8   	1000000000	         0.5508 ns/op
-// SeaHashIndexedWordDB-8   1000000000	         0.5473 ns/op
-func BenchmarkTestHashingForIndexedWordDB(b *testing.B) {
-	b.Run("xxHash hasher", testHasher(UseXXHashID))
-	b.Run("seaHash hasher", testHasher(UseXXHashID)) //TODO Use other hasher
+func TestIndexRejectsWordsItCannotHold(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	_, err = NewIndex(*log, []string{"chunk", ""})
+	assert.EqualError(t, err, fmt.Sprintf("word [] must have between 1 and %d letters", MaxWordLength))
+
+	_, err = NewIndex(*log, []string{"chunk", strings.Repeat("a", MaxWordLength+1)})
+	assert.Error(t, err)
 }
 
-func testHasher(id IDFn) func(b *testing.B) {
-	return func(b *testing.B) {
-		log, err := wordle.NewProductionLogger(b.Name())
-		require.NoError(b, err)
+func TestSearchExcludesAbsentLetters(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
 
-		rand.Seed(time.Now().UnixNano())
-		size := 1000000
-		var words = make([]string, size)
-		var deduped = map[string]bool{}
-		for i := 0; i < size; i++ {
-			letters := randomString()
-			words[i] = letters
-			deduped[letters] = true
-		}
+	db, err := NewIndex(*log, []string{"chunk", "latch", "crane", "tread"})
+	require.NoError(t, err)
+
+	guess, err := NewWordleSearch("slate", []Knowlege{Absent, None, None, None, None})
+	require.NoError(t, err)
+
+	result, err := db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chunk", "crane", "latch", "tread"}, result.Items)
+
+	guess, err = NewWordleSearch("cable", []Knowlege{Full, None, None, Absent, None})
+	require.NoError(t, err)
 
-		db, err := NewIndex(*log, words, id)
+	result, err = db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chunk", "crane"}, result.Items)
+}
+
+func BenchmarkIndexing(b *testing.B) {
+	log, err := wordle.NewProductionLogger(b.Name())
+	require.NoError(b, err)
+
+	rand.Seed(time.Now().UnixNano())
+	size := 1000000
+	var words = make([]string, size)
+	var deduped = map[string]bool{}
+	for i := 0; i < size; i++ {
+		letters := randomString()
+		words[i] = letters
+		deduped[letters] = true
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		db, err := NewIndex(*log, words)
 		require.NoError(b, err)
-		b.Logf("index contains %v items", db.size)
-		assert.Equal(b, db.size, len(deduped))
-		PrintMemUsage()
+		assert.Equal(b, len(deduped), len(db.words))
 	}
+	PrintMemUsage()
 }
 
 func randomString() string {
@@ -120,19 +179,200 @@ func bToMb(b uint64) uint64 {
 	return b / 1024 / 1024
 }
 
-var UseFixedHasher IDFn = NewHashingIDFn(func() hash.Hash64 {
-	var h hash.Hash64 = &fixedHasher{}
-	return h
-})
+func TestSearchWithRepeatedLetterCounts(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"those", "chose", "geese", "tense", "these", "shone", "sheet"})
+	require.NoError(t, err)
+
+	// the grey e's say "those" has exactly one e, even though it is also green in the last position
+	guess, err := NewWordleSearch("geese", BuildKnowledgeForGuess("those", "geese"))
+	require.NoError(t, err)
+
+	result, err := db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chose", "those"}, result.Items)
+
+	// two yellow e's and a grey one mean the wordle has exactly two e's, none of them where they were guessed
+	guess, err = NewWordleSearch("eerie", []Knowlege{Present, Present, Absent, Absent, Absent})
+	require.NoError(t, err)
+
+	result, err = db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sheet"}, result.Items)
+}
+
+func TestSearchExcludesYellowLettersFromTheirGuessedPosition(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"arise", "raise", "sober", "roast", "birds"})
+	require.NoError(t, err)
+
+	guess, err := NewWordleSearch("rates", []Knowlege{Present, None, None, None, None})
+	require.NoError(t, err)
+
+	result, err := db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arise", "birds", "sober"}, result.Items)
+}
+
+func TestIndexServesSeveralWordLengths(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"tram", "team", "crate", "trace", "charts", "stream", "reacted"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{4, 5, 6, 7}, db.Lengths())
+	assert.Equal(t, 2, db.SizeOfLength(4))
+	assert.Equal(t, 0, db.SizeOfLength(8))
+
+	tests := []struct {
+		letters string
+		results []string
+	}{
+		{"mast", []string{"team", "tram"}},
+		{"roost", []string{"crate", "trace"}},
+		{"thinks", []string{"charts", "stream"}},
+		{"elegant", []string{"reacted"}},
+	}
+
+	for _, tt := range tests {
+		knowledge := NoKnowledgeOf(len(tt.letters))
+		knowledge[0] = Present
+
+		guess, err := NewWordleSearch(tt.letters, knowledge)
+		require.NoError(t, err)
+
+		result, err := db.Search(*guess)
+		require.NoError(t, err)
+		assert.Equal(t, tt.results, result.Items, tt.letters)
+
+		assert.Len(t, db.PickRandomWordOfLength(len(tt.letters)), len(tt.letters))
+	}
+
+	_, err = db.CandidateGuess("abcdefghi")
+	assert.EqualError(t, err, "the index has no words of length 9")
+}
+
+func TestBitsetSearchAgreesWithConstraintMatching(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words)
+	require.NoError(t, err)
+
+	for i := 0; i < 200; i++ {
+		answer := db.PickRandomWordOfLength(5)
+		c := NewConstraints(5)
+		for guesses := 0; guesses < 1+rand.Intn(3); guesses++ {
+			guess := db.PickRandomWordOfLength(5)
+			c.Add(Wordle{letters: guess, knowledge: BuildKnowledgeForGuess(answer, guess)})
+		}
+
+		var expected []string
+		for _, word := range words {
+			if c.Matches(word) {
+				expected = append(expected, word)
+			}
+		}
+
+		assert.Equal(t, expected, db.Filter(c))
+		assert.Contains(t, db.Filter(c), answer)
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	log, err := wordle.NewProductionLogger(b.Name())
+	require.NoError(b, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(b, err)
+
+	db, err := NewIndex(*log, words)
+	require.NoError(b, err)
 
-type fixedHasher struct{ p []byte }
+	guess, err := NewWordleSearch("crane", BuildKnowledgeForGuess("those", "crane"))
+	require.NoError(b, err)
 
-func (f *fixedHasher) Sum64() uint64       { return uint64(1) }
-func (f *fixedHasher) Sum(b []byte) []byte { return b }
-func (f *fixedHasher) Reset()              {}
-func (f *fixedHasher) BlockSize() int      { return 1 }
-func (f *fixedHasher) Size() int           { return len(f.p) }
-func (f *fixedHasher) Write(p []byte) (n int, err error) {
-	f.p = p
-	return len(p), nil
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = db.Search(*guess)
+	}
+}
+
+func TestIndexSeparatesAnswersFromGuesses(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndexWithAnswers(*log, []string{"aahed", "crwth", "clomp", "tram"}, []string{"Batch", "catch", "hatch", "latch", "match", "team"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aahed", "batch", "catch", "clomp", "crwth", "hatch", "latch", "match"}, db.Words(5))
+	assert.Equal(t, []string{"batch", "catch", "hatch", "latch", "match"}, db.Answers(5))
+	assert.True(t, db.IsAnswer("batch"))
+	assert.False(t, db.IsAnswer("clomp"))
+	assert.False(t, db.IsAnswer("zzzzz"))
+	assert.True(t, db.Contains("clomp"))
+
+	guess, err := NewWordleSearch("witch", []Knowlege{Absent, Absent, Full, Full, Full})
+	require.NoError(t, err)
+
+	result, err := db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch", "catch", "hatch", "latch", "match"}, result.Items)
+
+	engineResult, err := NewSearchEngine(db).Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch", "catch", "hatch", "latch", "match"}, engineResult.Items)
+
+	suggestions, err := NewSolver(db, EntropyStrategy{}).Suggest(NewConstraints(5), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "clomp", suggestions[0].Word)
+	assert.False(t, suggestions[0].Candidate)
+}
+
+func TestSearchOrdersResultsByPrior(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"batch", "catch", "hatch", "latch", "match", "patch", "watch"})
+	require.NoError(t, err)
+
+	guess, err := NewWordleSearch("witch", []Knowlege{Absent, Absent, Full, Full, Full})
+	require.NoError(t, err)
+
+	result, err := db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch", "catch", "hatch", "latch", "match", "patch"}, result.Items)
+
+	db.SetPriors(map[string]float64{"match": 5000, "catch": 1200, "patch": 1200, "watch": 9000})
+	assert.Equal(t, 5001.0, db.Prior("match"))
+	assert.Equal(t, 1.0, db.Prior("hatch"))
+	assert.Equal(t, 1.0, db.Prior("zzzzz"))
+
+	result, err = db.Search(*guess)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"match", "catch", "patch", "batch", "hatch", "latch"}, result.Items)
+}
+
+func TestPickRandomWordCoversEveryWord(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words := []string{"apple", "eerie", "ebony", "zebra"}
+	db, err := NewIndex(*log, words)
+	require.NoError(t, err)
+
+	picked := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		picked[db.PickRandomWord()] = true
+	}
+	assert.Len(t, picked, len(words), "words starting with e are picked too")
+	assert.Len(t, Alphabet, 26)
 }
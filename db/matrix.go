@@ -0,0 +1,228 @@
+package db
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash"
+	"github.com/howzat/wordle"
+	"github.com/pkg/errors"
+)
+
+const (
+	matrixMagic      = "WPMX"
+	matrixVersion    = 1
+	matrixHeaderSize = 32
+	MaxMatrixLength  = 5 // the longest word whose patterns fit in a byte
+)
+
+/*
+PatternMatrix holds the feedback pattern of every allowed guess against every answer, one byte per pair with a row
+per guess, so a lookup is a single array index.
+
+On disk the matrix is a 32 byte little endian header followed by the rows:
+
+	magic "WPMX" | version uint32 | dictionary hash uint64 | word length uint32 | guesses uint32 | answers uint32 | 4 bytes padding
+
+The dictionary hash is DictionaryHash of the guesses and answers in the order they were indexed, so a file is never
+used with a dictionary it was not built from.
+*/
+type PatternMatrix struct {
+	guesses   []string
+	answers   []string
+	guessIDs  map[string]int
+	answerIDs map[string]int
+	data      []byte
+	release   func() error
+}
+
+func BuildPatternMatrix(guesses []string, answers []string) (*PatternMatrix, error) {
+	m, err := newPatternMatrix(guesses, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	m.data = make([]byte, len(guesses)*len(answers))
+	rows := make(chan int, len(guesses))
+	for g := range guesses {
+		rows <- g
+	}
+	close(rows)
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range rows {
+				row := m.data[g*len(answers) : (g+1)*len(answers)]
+				for a, answer := range answers {
+					row[a] = byte(FeedbackPattern(answer, guesses[g]))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return m, nil
+}
+
+func newPatternMatrix(guesses []string, answers []string) (*PatternMatrix, error) {
+	if len(guesses) == 0 || len(answers) == 0 {
+		return nil, errors.New("a pattern matrix needs at least one guess and one answer")
+	}
+
+	length := len(guesses[0])
+	if length > MaxMatrixLength {
+		return nil, errors.Errorf("pattern matrices only support words of up to %d letters", MaxMatrixLength)
+	}
+
+	m := &PatternMatrix{
+		guesses:   guesses,
+		answers:   answers,
+		guessIDs:  make(map[string]int, len(guesses)),
+		answerIDs: make(map[string]int, len(answers)),
+		release:   func() error { return nil },
+	}
+
+	for i, g := range guesses {
+		if len(g) != length {
+			return nil, errors.Errorf("guess [%v] is not %d letters long", g, length)
+		}
+		m.guessIDs[g] = i
+	}
+
+	for i, a := range answers {
+		if len(a) != length {
+			return nil, errors.Errorf("answer [%v] is not %d letters long", a, length)
+		}
+		m.answerIDs[a] = i
+	}
+	return m, nil
+}
+
+// DictionaryHash identifies the ordered guesses and answers a matrix was built from
+func DictionaryHash(guesses []string, answers []string) uint64 {
+	h := xxhash.New()
+	_, _ = h.Write([]byte(strings.Join(guesses, "\n")))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(strings.Join(answers, "\n")))
+	return h.Sum64()
+}
+
+func (m *PatternMatrix) Pattern(guess int, answer int) Pattern {
+	return Pattern(m.data[guess*len(m.answers)+answer])
+}
+
+func (m *PatternMatrix) Lookup(guess string, answer string) (Pattern, bool) {
+	g, ok := m.guessIDs[guess]
+	if !ok {
+		return 0, false
+	}
+
+	a, ok := m.answerIDs[answer]
+	if !ok {
+		return 0, false
+	}
+	return m.Pattern(g, a), true
+}
+
+func (m *PatternMatrix) Length() int {
+	return len(m.guesses[0])
+}
+
+func (m *PatternMatrix) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, matrixHeaderSize)
+	copy(header, matrixMagic)
+	binary.LittleEndian.PutUint32(header[4:], matrixVersion)
+	binary.LittleEndian.PutUint64(header[8:], DictionaryHash(m.guesses, m.answers))
+	binary.LittleEndian.PutUint32(header[16:], uint32(m.Length()))
+	binary.LittleEndian.PutUint32(header[20:], uint32(len(m.guesses)))
+	binary.LittleEndian.PutUint32(header[24:], uint32(len(m.answers)))
+
+	n, err := w.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+
+	d, err := w.Write(m.data)
+	return int64(n + d), err
+}
+
+// OpenPatternMatrix memory maps a matrix file, failing unless it was built from exactly these guesses and answers
+func OpenPatternMatrix(path string, guesses []string, answers []string) (*PatternMatrix, error) {
+	m, err := newPatternMatrix(guesses, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, wordle.WrapErr(err, "error opening pattern matrix [%v]", path)
+	}
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, wordle.WrapErr(err, "error reading pattern matrix [%v]", path)
+	}
+
+	header := make([]byte, matrixHeaderSize)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return nil, wordle.WrapErr(err, "error reading pattern matrix header [%v]", path)
+	}
+
+	if err := checkMatrixHeader(header, m); err != nil {
+		return nil, errors.Wrapf(err, "pattern matrix [%v]", path)
+	}
+
+	// the header matches, a file of any other size was cut short or written to since
+	expected := int64(matrixHeaderSize + len(guesses)*len(answers))
+	if info.Size() != expected {
+		return nil, errors.Errorf("pattern matrix [%v] has %d bytes but %d were expected", path, info.Size(), expected)
+	}
+
+	mapped, release, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, wordle.WrapErr(err, "error mapping pattern matrix [%v]", path)
+	}
+
+	m.data = mapped[matrixHeaderSize:]
+	m.release = release
+	return m, nil
+}
+
+func checkMatrixHeader(header []byte, m *PatternMatrix) error {
+	if string(header[:4]) != matrixMagic {
+		return errors.New("is not a pattern matrix file")
+	}
+
+	if v := binary.LittleEndian.Uint32(header[4:]); v != matrixVersion {
+		return errors.Errorf("has version %d but only version %d is supported", v, matrixVersion)
+	}
+
+	if l := binary.LittleEndian.Uint32(header[16:]); int(l) != m.Length() {
+		return errors.Errorf("was built for %d letter words, not %d", l, m.Length())
+	}
+
+	if g, a := binary.LittleEndian.Uint32(header[20:]), binary.LittleEndian.Uint32(header[24:]); int(g) != len(m.guesses) || int(a) != len(m.answers) {
+		return errors.Errorf("was built from %d guesses and %d answers, not %d and %d", g, a, len(m.guesses), len(m.answers))
+	}
+
+	if h := binary.LittleEndian.Uint64(header[8:]); h != DictionaryHash(m.guesses, m.answers) {
+		return errors.Errorf("was built from a different dictionary (%016x)", h)
+	}
+	return nil
+}
+
+// Close releases the memory mapped file, the matrix must not be used afterwards
+func (m *PatternMatrix) Close() error {
+	return m.release()
+}
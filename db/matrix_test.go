@@ -0,0 +1,107 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternMatrixMatchesFeedback(t *testing.T) {
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	guesses, answers := words[:400], words[200:700]
+	m, err := BuildPatternMatrix(guesses, answers)
+	require.NoError(t, err)
+
+	for g, guess := range guesses {
+		for a, answer := range answers {
+			require.Equal(t, FeedbackPattern(answer, guess), m.Pattern(g, a))
+		}
+	}
+
+	p, ok := m.Lookup("crane", answers[0])
+	assert.False(t, ok)
+	assert.Equal(t, Pattern(0), p)
+}
+
+func TestPatternMatrixFileRoundTrip(t *testing.T) {
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	guesses, answers := words[:300], words[100:400]
+	m, err := BuildPatternMatrix(guesses, answers)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "patterns.wpm")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	n, err := m.WriteTo(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, int64(32+300*300), n)
+
+	mapped, err := OpenPatternMatrix(path, guesses, answers)
+	require.NoError(t, err)
+
+	for g := range guesses {
+		for a := range answers {
+			require.Equal(t, m.Pattern(g, a), mapped.Pattern(g, a))
+		}
+	}
+	require.NoError(t, mapped.Close())
+
+	_, err = OpenPatternMatrix(path, answers, guesses)
+	assert.EqualError(t, err, fmt.Sprintf("pattern matrix [%s]: was built from a different dictionary (%016x)", path, DictionaryHash(guesses, answers)))
+
+	_, err = OpenPatternMatrix(path, guesses[:299], answers)
+	assert.EqualError(t, err, "pattern matrix ["+path+"]: was built from 300 guesses and 300 answers, not 299 and 300")
+
+	_, err = OpenPatternMatrix(path, []string{"bank", "crab"}, []string{"kerb"})
+	assert.EqualError(t, err, "pattern matrix ["+path+"]: was built for 5 letter words, not 4")
+
+	require.NoError(t, os.Truncate(path, 32+300*299))
+	_, err = OpenPatternMatrix(path, guesses, answers)
+	assert.EqualError(t, err, "pattern matrix ["+path+"] has 89732 bytes but 90032 were expected")
+}
+
+func TestPatternMatrixRejectsLongWords(t *testing.T) {
+	_, err := BuildPatternMatrix([]string{"charts"}, []string{"stream"})
+	assert.EqualError(t, err, "pattern matrices only support words of up to 5 letters")
+
+	_, err = BuildPatternMatrix([]string{"chart"}, []string{"stream"})
+	assert.EqualError(t, err, "answer [stream] is not 5 letters long")
+}
+
+func TestSolverSuggestionsAreTheSameWithAPatternMatrix(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words[:2000])
+	require.NoError(t, err)
+
+	c := NewConstraints(5)
+	guess, err := NewWordleSearch("crane", BuildKnowledgeForGuess("abbey", "crane"))
+	require.NoError(t, err)
+	c.Add(*guess)
+
+	solver := NewSolver(db, EntropyStrategy{})
+	expected, err := solver.Suggest(c, 10)
+	require.NoError(t, err)
+
+	m, err := BuildPatternMatrix(db.Words(5), db.Words(5)[:1500])
+	require.NoError(t, err)
+	solver.UsePatternMatrix(m)
+
+	actual, err := solver.Suggest(c, 10)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,20 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package db
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the whole file where memory mapping is not available
+func mapFile(f *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error {
+		return nil
+	}, nil
+}
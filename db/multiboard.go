@@ -0,0 +1,143 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxGuessesForBoards is how many guesses the multi board variants allow, one more for each extra board
+func MaxGuessesForBoards(boards int) int {
+	return MaxGuesses + boards - 1
+}
+
+/*
+MultiSession tracks a multi board game, such as Dordle, Quordle or Octordle, where every guess is scored against each
+hidden word at once. Each board keeps its own Session, and once a board is solved it stops taking guesses.
+*/
+type MultiSession struct {
+	index   *Index
+	length  int
+	boards  []*Session
+	guesses []string
+}
+
+func NewMultiSession(index *Index, length int, boards int) (*MultiSession, error) {
+	if boards < 1 {
+		return nil, errors.Errorf("a multi board session needs at least 1 board, not %d", boards)
+	}
+
+	m := &MultiSession{
+		index:  index,
+		length: length,
+		boards: make([]*Session, boards),
+	}
+	for i := range m.boards {
+		m.boards[i] = NewSession(index, length)
+	}
+	return m, nil
+}
+
+/*
+Add scores a guess on every board, feedback holding one row per board in board order. The row for a board that is
+already solved is ignored and may be nil.
+*/
+func (m *MultiSession) Add(guess string, feedback [][]Knowlege) error {
+	if m.Over() {
+		return errors.New("multi board session is already over")
+	}
+	if len(feedback) != len(m.boards) {
+		return errors.Errorf("guess [%v] has feedback for %d boards, the session has %d", guess, len(feedback), len(m.boards))
+	}
+
+	guess = strings.ToLower(guess)
+	if len(guess) != m.length {
+		return errors.Errorf("guess [%v] does not match the session word length of %d", guess, m.length)
+	}
+
+	rows := make([]*Wordle, len(m.boards))
+	for i, board := range m.boards {
+		if board.Solved() {
+			continue
+		}
+		if feedback[i] == nil {
+			return errors.Errorf("board %d is not solved but has no feedback for guess [%v]", i+1, guess)
+		}
+
+		w, err := NewWordleSearch(guess, feedback[i])
+		if err != nil {
+			return errors.Wrapf(err, "board %d", i+1)
+		}
+		if err := board.accepts(*w, MaxGuessesForBoards(len(m.boards))); err != nil {
+			return errors.Wrapf(err, "board %d", i+1)
+		}
+		rows[i] = w
+	}
+
+	// every row is checked before any board is changed so a bad row leaves the session as it was
+	for i, board := range m.boards {
+		if rows[i] != nil {
+			board.guesses = append(board.guesses, *rows[i])
+		}
+	}
+
+	m.guesses = append(m.guesses, guess)
+	return nil
+}
+
+func (m *MultiSession) Length() int {
+	return m.length
+}
+
+func (m *MultiSession) Boards() int {
+	return len(m.boards)
+}
+
+func (m *MultiSession) Board(i int) *Session {
+	return m.boards[i]
+}
+
+func (m *MultiSession) Guesses() []string {
+	return m.guesses
+}
+
+func (m *MultiSession) Solved(board int) bool {
+	return m.boards[board].Solved()
+}
+
+// Unsolved lists the boards still in play
+func (m *MultiSession) Unsolved() []int {
+	var unsolved []int
+	for i, board := range m.boards {
+		if !board.Solved() {
+			unsolved = append(unsolved, i)
+		}
+	}
+	return unsolved
+}
+
+func (m *MultiSession) AllSolved() bool {
+	return len(m.Unsolved()) == 0
+}
+
+// Over is true once every board is solved or every guess has been used
+func (m *MultiSession) Over() bool {
+	return m.AllSolved() || len(m.guesses) >= MaxGuessesForBoards(len(m.boards))
+}
+
+// Candidates lists the answers still possible on the board, most likely first
+func (m *MultiSession) Candidates(board int) []string {
+	return m.index.OrderByLikelihood(m.index.Filter(m.boards[board].Constraints()))
+}
+
+// Suggest recommends the next guess over every unsolved board, see Solver.SuggestBoards
+func (m *MultiSession) Suggest(solver *Solver, limit int) ([]Suggestion, error) {
+	var boards []*Constraints
+	for _, i := range m.Unsolved() {
+		boards = append(boards, m.boards[i].Constraints())
+	}
+	if len(boards) == 0 {
+		return nil, errors.New("every board is already solved")
+	}
+	return solver.SuggestBoards(boards, limit)
+}
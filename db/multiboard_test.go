@@ -0,0 +1,149 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSessionTracksEachBoard(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"brick", "crane", "crate", "flock", "plumb", "slate", "trace"})
+	require.NoError(t, err)
+
+	m, err := NewMultiSession(db, 5, 2)
+	require.NoError(t, err)
+
+	answers := []string{"crate", "flock"}
+	play := func(guess string) error {
+		var feedback [][]Knowlege
+		for i, answer := range answers {
+			if m.Solved(i) {
+				feedback = append(feedback, nil)
+			} else {
+				feedback = append(feedback, BuildKnowledgeForGuess(answer, guess))
+			}
+		}
+		return m.Add(guess, feedback)
+	}
+
+	require.NoError(t, play("slate"))
+	assert.Contains(t, m.Candidates(0), "crate")
+	assert.NotContains(t, m.Candidates(0), "slate")
+	assert.Equal(t, []string{"flock", "plumb"}, m.Candidates(1), "both contain the yellow l")
+
+	require.NoError(t, play("crate"))
+	assert.True(t, m.Solved(0))
+	assert.Equal(t, []int{1}, m.Unsolved())
+	assert.Equal(t, []string{"flock"}, m.Candidates(1))
+	assert.False(t, m.Over())
+
+	require.NoError(t, play("flock"))
+	assert.True(t, m.AllSolved())
+	assert.True(t, m.Over())
+	assert.Equal(t, []string{"slate", "crate", "flock"}, m.Guesses())
+	assert.Len(t, m.Board(0).Guesses(), 2, "a solved board stops taking guesses")
+
+	assert.EqualError(t, play("brick"), "multi board session is already over")
+}
+
+func TestMultiSessionRejectsBadFeedback(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"crane", "crate", "slate"})
+	require.NoError(t, err)
+
+	_, err = NewMultiSession(db, 5, 0)
+	assert.EqualError(t, err, "a multi board session needs at least 1 board, not 0")
+
+	m, err := NewMultiSession(db, 5, 4)
+	require.NoError(t, err)
+
+	row := BuildKnowledgeForGuess("crate", "slate")
+	assert.EqualError(t, m.Add("slate", [][]Knowlege{row, row}), "guess [slate] has feedback for 2 boards, the session has 4")
+	assert.EqualError(t, m.Add("slate", [][]Knowlege{row, row, nil, row}), "board 3 is not solved but has no feedback for guess [slate]")
+	assert.EqualError(t, m.Add("slate", [][]Knowlege{row, row, row[:4], row}), "board 3: knowledge must have exactly 5 items")
+	assert.Empty(t, m.Board(0).Guesses(), "a rejected guess changes no board")
+
+	full, err := NewWordleSearch("slate", row)
+	require.NoError(t, err)
+	m.boards[2].guesses = []Wordle{*full, *full, *full, *full, *full, *full, *full, *full, *full}
+	assert.EqualError(t, m.Add("slate", [][]Knowlege{row, row, row, row}), "board 3: session already has 9 guesses")
+	assert.Empty(t, m.Board(0).Guesses(), "a board that cannot take the guess leaves the others as they were")
+	m.boards[2].guesses = nil
+
+	assert.Equal(t, 9, MaxGuessesForBoards(4))
+	for i := 0; i < 9; i++ {
+		require.NoError(t, m.Add("slate", [][]Knowlege{row, row, row, row}))
+	}
+	assert.True(t, m.Over())
+}
+
+func TestSuggestBoardsCombinesEachBoard(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words[:2000])
+	require.NoError(t, err)
+
+	solver := NewSolver(db, EntropyStrategy{})
+
+	first := NewConstraints(5)
+	first.Add(wordleOf(t, "crane", BuildKnowledgeForGuess(words[10], "crane")))
+	second := NewConstraints(5)
+	second.Add(wordleOf(t, "crane", BuildKnowledgeForGuess(words[1500], "crane")))
+
+	combined, err := solver.SuggestBoards([]*Constraints{first, second}, 0)
+	require.NoError(t, err)
+
+	firstScores := scoresByWord(solver.Rank(db.Words(5), db.Filter(first), 0))
+	secondScores := scoresByWord(solver.Rank(db.Words(5), db.Filter(second), 0))
+	for _, s := range combined[:20] {
+		assert.InDelta(t, firstScores[s.Word].ExpectedBits+secondScores[s.Word].ExpectedBits, s.ExpectedBits, 1e-9, s.Word)
+		assert.Equal(t, firstScores[s.Word].WorstCase+secondScores[s.Word].WorstCase, s.WorstCase, s.Word)
+	}
+
+	for i := 1; i < len(combined); i++ {
+		assert.GreaterOrEqual(t, combined[i-1].ExpectedBits, combined[i].ExpectedBits)
+	}
+}
+
+func TestSuggestBoardsSolvesALastCandidateFirst(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"brick", "crane", "crate", "flock", "plumb", "slate", "trace"})
+	require.NoError(t, err)
+
+	m, err := NewMultiSession(db, 5, 2)
+	require.NoError(t, err)
+	require.NoError(t, m.Add("brick", [][]Knowlege{BuildKnowledgeForGuess("plumb", "brick"), BuildKnowledgeForGuess("crate", "brick")}))
+	require.Equal(t, []string{"plumb"}, m.Candidates(0))
+
+	suggestions, err := m.Suggest(NewSolver(db, EntropyStrategy{}), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "plumb", suggestions[0].Word)
+	assert.Len(t, suggestions, 3)
+}
+
+func wordleOf(t *testing.T, guess string, knowledge []Knowlege) Wordle {
+	w, err := NewWordleSearch(guess, knowledge)
+	require.NoError(t, err)
+	return *w
+}
+
+func scoresByWord(suggestions []Suggestion) map[string]Suggestion {
+	scores := map[string]Suggestion{}
+	for _, s := range suggestions {
+		scores[s.Word] = s
+	}
+	return scores
+}
@@ -0,0 +1,114 @@
+package db
+
+// Pattern is the feedback for a guess encoded in base 3, the first letter being the least significant digit.
+// Absent is 0, Present is 1 and Full is 2 so a solved five letter wordle is 242.
+type Pattern uint32
+
+// NoPattern is never the feedback of a guess, 3^MaxWordLength patterns still fit below it
+const NoPattern Pattern = 1<<32 - 1
+
+func PatternOf(knowledge []Knowlege) Pattern {
+	var p Pattern
+	for i := len(knowledge) - 1; i >= 0; i-- {
+		p = p*3 + Pattern(digitOf(knowledge[i]))
+	}
+	return p
+}
+
+func (p Pattern) Knowledge(length int) []Knowlege {
+	k := make([]Knowlege, length)
+	for i := range k {
+		switch p % 3 {
+		case 2:
+			k[i] = Full
+		case 1:
+			k[i] = Present
+		default:
+			k[i] = Absent
+		}
+		p /= 3
+	}
+	return k
+}
+
+// Digits writes the pattern one digit per letter in letter order, so Full, Present, Absent, Absent, Full is "21002"
+func (p Pattern) Digits(length int) string {
+	digits := make([]byte, length)
+	for i := range digits {
+		digits[i] = byte('0' + p%3)
+		p /= 3
+	}
+	return string(digits)
+}
+
+func (p Pattern) Solved(length int) bool {
+	return p == SolvedPattern(length)
+}
+
+func SolvedPattern(length int) Pattern {
+	var p Pattern
+	for i := 0; i < length; i++ {
+		p = p*3 + 2
+	}
+	return p
+}
+
+// PatternCount is the number of distinct patterns for words of the given length
+func PatternCount(length int) int {
+	n := 1
+	for i := 0; i < length; i++ {
+		n *= 3
+	}
+	return n
+}
+
+func digitOf(k Knowlege) int {
+	switch k {
+	case Full:
+		return 2
+	case Present:
+		return 1
+	default:
+		return 0
+	}
+}
+
+/*
+FeedbackPattern is BuildKnowledgeForGuess without the allocations, for use in the solvers' inner loops. The wordle and
+guess must have the same length of at most MaxWordLength letters, otherwise there is no feedback and it returns
+NoPattern.
+*/
+func FeedbackPattern(wordle string, guess string) Pattern {
+	n := len(guess)
+	if n != len(wordle) || n > MaxWordLength {
+		return NoPattern
+	}
+
+	var unmatched [256]int8
+	var full [MaxWordLength]bool
+	for i := 0; i < n; i++ {
+		if wordle[i] == guess[i] {
+			full[i] = true
+		} else {
+			unmatched[wordle[i]]++
+		}
+	}
+
+	var p Pattern
+	for i := n - 1; i >= 0; i-- {
+		p *= 3
+		if full[i] {
+			p += 2
+		}
+	}
+
+	var pow Pattern = 1
+	for i := 0; i < n; i++ {
+		if !full[i] && unmatched[guess[i]] > 0 {
+			unmatched[guess[i]]--
+			p += pow
+		}
+		pow *= 3
+	}
+	return p
+}
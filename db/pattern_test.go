@@ -0,0 +1,48 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternRoundTrip(t *testing.T) {
+	k := []Knowlege{Full, Present, Absent, Absent, Full}
+	p := PatternOf(k)
+	assert.Equal(t, Pattern(2+1*3+2*81), p)
+	assert.Equal(t, k, p.Knowledge(5))
+
+	assert.Equal(t, Pattern(242), SolvedPattern(5))
+	assert.True(t, PatternOf([]Knowlege{Full, Full, Full, Full}).Solved(4))
+	assert.Equal(t, 243, PatternCount(5))
+}
+
+func TestFeedbackPatternAgreesWithBuildKnowledge(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words)
+	require.NoError(t, err)
+
+	for i := 0; i < 10000; i++ {
+		wordle := db.PickRandomWordOfLength(5)
+		guess := db.PickRandomWordOfLength(5)
+		assert.Equal(t, PatternOf(BuildKnowledgeForGuess(wordle, guess)), FeedbackPattern(wordle, guess), "%v %v", wordle, guess)
+	}
+
+	assert.Equal(t, PatternOf(BuildKnowledgeForGuess("those", "geese")), FeedbackPattern("those", "geese"))
+}
+
+func TestFeedbackPatternNeedsWordsOfTheSameLength(t *testing.T) {
+	assert.Equal(t, NoPattern, FeedbackPattern("those", "geeses"))
+	assert.Equal(t, NoPattern, FeedbackPattern("thoses", "geese"))
+	assert.Equal(t, NoPattern, FeedbackPattern(strings.Repeat("a", MaxWordLength+1), strings.Repeat("a", MaxWordLength+1)))
+	assert.Equal(t, SolvedPattern(MaxWordLength), FeedbackPattern(strings.Repeat("a", MaxWordLength), strings.Repeat("a", MaxWordLength)))
+	assert.Less(t, uint64(PatternCount(MaxWordLength)), uint64(NoPattern))
+}
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+ParseQuery compiles a compact, human typed query into constraints. A query is made of whitespace separated terms
+
+	s?a?e   the pattern, one letter or wildcard (? . or _) per position, letters are green
+	+r      letters the wordle contains, +ee requires at least two Es
+	-tiou   letters the wordle does not contain
+	r!2     a letter the wordle contains but not in the 2nd position, r!2,4 for several positions
+
+Terms may come in any order. Without a pattern the query is for a word of DefaultWordLength.
+*/
+func ParseQuery(query string) (*Constraints, error) {
+	p := queryParser{query: query}
+	return p.parse()
+}
+
+// SearchQuery parses the query and lists the matching answers most likely first
+func (d *Index) SearchQuery(query string) ([]string, error) {
+	c, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return d.OrderByLikelihood(d.Filter(c)), nil
+}
+
+// QueryError points at the character in the query that could not be parsed
+type QueryError struct {
+	Query   string
+	Offset  int
+	Message string
+}
+
+func (e QueryError) Error() string {
+	return fmt.Sprintf("%s at offset %d in query [%s]", e.Message, e.Offset, e.Query)
+}
+
+// Pointer shows the query with a caret under the offending character
+func (e QueryError) Pointer() string {
+	return e.Query + "\n" + strings.Repeat(" ", e.Offset) + "^"
+}
+
+const queryWildcards = "?._"
+
+type queryTerm struct {
+	text   string
+	offset int
+}
+
+type queryParser struct {
+	query      string
+	pattern    *queryTerm
+	greens     map[int]byte
+	required   map[byte]int
+	excluded   map[byte]int // the offset each excluded letter was given at
+	notAt      map[int]map[byte]int
+	patternLen int
+}
+
+func (p *queryParser) parse() (*Constraints, error) {
+	p.greens = map[int]byte{}
+	p.required = map[byte]int{}
+	p.excluded = map[byte]int{}
+	p.notAt = map[int]map[byte]int{}
+	p.patternLen = DefaultWordLength
+
+	terms := p.terms()
+	if len(terms) == 0 {
+		return nil, p.errorAt(0, "empty query")
+	}
+
+	for _, t := range terms {
+		if strings.ContainsAny(t.text[:1], "+-") || strings.Contains(t.text, "!") {
+			continue
+		}
+		if err := p.parsePattern(t); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range terms {
+		var err error
+		switch {
+		case t.text[0] == '+':
+			err = p.parseLetters(t, func(l byte, _ int) { p.required[l]++ })
+		case t.text[0] == '-':
+			err = p.parseLetters(t, func(l byte, offset int) {
+				if _, ok := p.excluded[l]; !ok {
+					p.excluded[l] = offset
+				}
+			})
+		case strings.Contains(t.text, "!"):
+			err = p.parsePositions(t)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.compile()
+}
+
+func (p *queryParser) terms() []queryTerm {
+	var terms []queryTerm
+	start := -1
+	for i, r := range p.query + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				terms = append(terms, queryTerm{text: p.query[start:i], offset: start})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return terms
+}
+
+func (p *queryParser) parsePattern(t queryTerm) error {
+	if p.pattern != nil {
+		return p.errorAt(t.offset, fmt.Sprintf("second pattern, the first was [%s]", p.pattern.text))
+	}
+	if len(t.text) > MaxWordLength {
+		return p.errorAt(t.offset+MaxWordLength, fmt.Sprintf("pattern is longer than %d letters", MaxWordLength))
+	}
+
+	for i := 0; i < len(t.text); i++ {
+		ch := t.text[i]
+		switch {
+		case strings.IndexByte(queryWildcards, ch) >= 0:
+		case isQueryLetter(ch):
+			p.greens[i] = lower(ch)
+		default:
+			return p.errorAt(t.offset+i, fmt.Sprintf("unexpected character [%c] in pattern", ch))
+		}
+	}
+
+	p.pattern = &t
+	p.patternLen = len(t.text)
+	return nil
+}
+
+func (p *queryParser) parseLetters(t queryTerm, add func(l byte, offset int)) error {
+	if len(t.text) == 1 {
+		return p.errorAt(t.offset+1, fmt.Sprintf("expected letters after [%c]", t.text[0]))
+	}
+
+	for i := 1; i < len(t.text); i++ {
+		if !isQueryLetter(t.text[i]) {
+			return p.errorAt(t.offset+i, fmt.Sprintf("unexpected character [%c], expected a letter", t.text[i]))
+		}
+		add(lower(t.text[i]), t.offset+i)
+	}
+	return nil
+}
+
+func (p *queryParser) parsePositions(t queryTerm) error {
+	bang := strings.IndexByte(t.text, '!')
+	if bang != 1 || !isQueryLetter(t.text[0]) {
+		return p.errorAt(t.offset, "expected a single letter before [!]")
+	}
+	l := lower(t.text[0])
+	if _, ok := p.required[l]; !ok {
+		p.required[l] = 0 // a letter excluded from a position is still in the word
+	}
+
+	offset := t.offset + bang + 1
+	for _, position := range strings.Split(t.text[bang+1:], ",") {
+		n, err := strconv.Atoi(position)
+		if err != nil || n < 1 || n > p.patternLen {
+			return p.errorAt(offset, fmt.Sprintf("expected a position between 1 and %d", p.patternLen))
+		}
+
+		if _, ok := p.notAt[n-1]; !ok {
+			p.notAt[n-1] = map[byte]int{}
+		}
+		p.notAt[n-1][l] = offset
+		offset += len(position) + 1
+	}
+	return nil
+}
+
+func (p *queryParser) compile() (*Constraints, error) {
+	c := NewConstraints(p.patternLen)
+
+	for i, l := range p.greens {
+		c.greens[i] = l
+		c.minCount[l]++
+	}
+
+	for l, n := range p.required {
+		if n == 0 {
+			n = 1
+		}
+		if n > c.minCount[l] {
+			c.minCount[l] = n
+		}
+	}
+
+	// conflicts are reported at the earliest offset so the same query always gives the same error
+	var conflict *QueryError
+	report := func(offset int, message string) {
+		if conflict == nil || offset < conflict.Offset {
+			conflict = &QueryError{Query: p.query, Offset: offset, Message: message}
+		}
+	}
+
+	for l, offset := range p.excluded {
+		if c.minCount[l] > 0 {
+			report(offset, fmt.Sprintf("letter [%c] is excluded but also required", l))
+		}
+		c.maxCount[l] = 0
+	}
+
+	for i, letters := range p.notAt {
+		for l, offset := range letters {
+			if c.greens[i] == l {
+				report(offset, fmt.Sprintf("letter [%c] is excluded from position %d but is green there", l, i+1))
+			}
+			c.exclude(i, l)
+		}
+	}
+
+	if conflict != nil {
+		return nil, *conflict
+	}
+	return c, nil
+}
+
+func (p *queryParser) errorAt(offset int, message string) error {
+	return QueryError{
+		Query:   p.query,
+		Offset:  offset,
+		Message: message,
+	}
+}
+
+func isQueryLetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func lower(ch byte) byte {
+	if ch >= 'A' && ch <= 'Z' {
+		return ch + 'a' - 'A'
+	}
+	return ch
+}
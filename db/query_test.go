@@ -0,0 +1,104 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	c, err := ParseQuery("s?a?e +r -tiou r!2")
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, c.Length())
+	assert.Equal(t, map[int]string{0: "s", 2: "a", 4: "e"}, c.Greens())
+	assert.Equal(t, []string{"a", "e", "r", "s"}, c.RequiredLetters())
+	assert.Equal(t, []string{"i", "o", "t", "u"}, c.AbsentLetters())
+	assert.Equal(t, []string{"r"}, c.ExcludedAt(1))
+
+	assert.True(t, c.Matches("snare"))
+	assert.False(t, c.Matches("shale"), "does not contain r")
+	assert.False(t, c.Matches("srace"), "r is excluded from the 2nd position")
+	assert.False(t, c.Matches("stare"), "contains t")
+}
+
+func TestParseQueryTerms(t *testing.T) {
+	c, err := ParseQuery("-x  +EE e!1,3 ......")
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, c.Length(), "the pattern sets the length wherever it appears")
+	assert.Equal(t, 2, c.MinCount("e"))
+	assert.Equal(t, []string{"e"}, c.ExcludedAt(0))
+	assert.Equal(t, []string{"e"}, c.ExcludedAt(2))
+	assert.Equal(t, 0, c.MaxCount("x"))
+
+	c, err = ParseQuery("e!3")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWordLength, c.Length())
+	assert.Equal(t, 1, c.MinCount("e"), "a letter excluded from a position is still in the word")
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	var tests = []struct {
+		query   string
+		offset  int
+		message string
+	}{
+		{"", 0, "empty query"},
+		{"s?a#e", 3, "unexpected character [#] in pattern"},
+		{"s?a?e cr?ne", 6, "second pattern, the first was [s?a?e]"},
+		{"s?a?e +", 7, "expected letters after [+]"},
+		{"s?a?e -ti0u", 9, "unexpected character [0], expected a letter"},
+		{"s?a?e r!6", 8, "expected a position between 1 and 5"},
+		{"s?a?e r!2,x", 10, "expected a position between 1 and 5"},
+		{"s?a?e rs!2", 6, "expected a single letter before [!]"},
+		{"s?a?e +r -tri", 11, "letter [r] is excluded but also required"},
+		{"s?a?e -s", 7, "letter [s] is excluded but also required"},
+		{"s?a?e a!3", 8, "letter [a] is excluded from position 3 but is green there"},
+		{"??????????????????????", 20, "pattern is longer than 20 letters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			require.Error(t, err)
+
+			var queryErr QueryError
+			require.ErrorAs(t, err, &queryErr)
+			assert.Equal(t, tt.offset, queryErr.Offset)
+			assert.Equal(t, tt.message, queryErr.Message)
+		})
+	}
+}
+
+func TestQueryErrorPointer(t *testing.T) {
+	_, err := ParseQuery("s?a#e")
+	require.Error(t, err)
+
+	assert.EqualError(t, err, "unexpected character [#] in pattern at offset 3 in query [s?a#e]")
+	assert.Equal(t, "s?a#e\n   ^", err.(QueryError).Pointer())
+}
+
+func TestSearchQueryMatchesWordleSearch(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words)
+	require.NoError(t, err)
+
+	search, err := NewWordleSearch("stare", []Knowlege{Full, Absent, Full, Absent, Full})
+	require.NoError(t, err)
+
+	expected, err := db.Search(*search)
+	require.NoError(t, err)
+
+	results, err := db.SearchQuery("s?a?e -tr")
+	require.NoError(t, err)
+	assert.Equal(t, expected.Items, results)
+	assert.NotEmpty(t, results)
+}
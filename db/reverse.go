@@ -0,0 +1,136 @@
+package db
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+/*
+ParseFeedbackGrid reads a shared result grid, one row of feedback per line in any of the feedback styles. Blank lines
+and anything before the first row, such as the "Wordle 1,234 4/6" header of the share sheet, are skipped.
+*/
+func ParseFeedbackGrid(grid string) ([][]Knowlege, error) {
+	var rows [][]Knowlege
+	for n, line := range strings.Split(grid, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		row, err := ParseFeedback(line)
+		if err != nil {
+			if len(rows) == 0 {
+				continue
+			}
+			return nil, errors.Wrapf(err, "line %d of the grid", n+1)
+		}
+
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, errors.Errorf("line %d of the grid has %d letters, the first row has %d", n+1, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("the grid has no rows of feedback")
+	}
+	return rows, nil
+}
+
+// GridMatch is an answer consistent with a grid and, for each row, the guesses that would have produced it
+type GridMatch struct {
+	Answer string
+	Rows   [][]string
+}
+
+/*
+ReverseGrid runs the feedback backwards: it lists the answers for which every row of the grid could have been
+produced by some valid guess, most likely first, along with those guesses. Only the last row may be solved.
+*/
+func (d *Index) ReverseGrid(grid [][]Knowlege) ([]GridMatch, error) {
+	if len(grid) == 0 {
+		return nil, errors.New("the grid has no rows of feedback")
+	}
+
+	length := len(grid[0])
+	patterns := make([]Pattern, len(grid))
+	for i, row := range grid {
+		if len(row) != length {
+			return nil, errors.Errorf("row %d of the grid has %d letters, the first row has %d", i+1, len(row), length)
+		}
+		for _, k := range row {
+			if k == None {
+				return nil, errors.Errorf("row %d of the grid has a letter without feedback", i+1)
+			}
+		}
+
+		patterns[i] = PatternOf(row)
+		if patterns[i].Solved(length) && i != len(grid)-1 {
+			return nil, errors.Errorf("row %d of the grid is solved but is not the last row", i+1)
+		}
+	}
+
+	answers := d.OrderByLikelihood(d.Answers(length))
+	guesses := d.Words(length)
+	matches := make([]*GridMatch, len(answers))
+
+	work := make(chan int, len(answers))
+	for i := range answers {
+		work <- i
+	}
+	close(work)
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				matches[i] = reverseAnswer(answers[i], guesses, patterns)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var consistent []GridMatch
+	for _, m := range matches {
+		if m != nil {
+			consistent = append(consistent, *m)
+		}
+	}
+	return consistent, nil
+}
+
+// reverseAnswer gives up on the answer at the first row no guess could have produced before listing every guess
+func reverseAnswer(answer string, guesses []string, patterns []Pattern) *GridMatch {
+	for _, p := range patterns {
+		if !anyGuessProduces(answer, guesses, p) {
+			return nil
+		}
+	}
+
+	m := &GridMatch{
+		Answer: answer,
+		Rows:   make([][]string, len(patterns)),
+	}
+	for _, guess := range guesses {
+		feedback := FeedbackPattern(answer, guess)
+		for r, p := range patterns {
+			if feedback == p {
+				m.Rows[r] = append(m.Rows[r], guess)
+			}
+		}
+	}
+	return m
+}
+
+func anyGuessProduces(answer string, guesses []string, p Pattern) bool {
+	for _, guess := range guesses {
+		if FeedbackPattern(answer, guess) == p {
+			return true
+		}
+	}
+	return false
+}
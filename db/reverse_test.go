@@ -0,0 +1,89 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeedbackGrid(t *testing.T) {
+	grid, err := ParseFeedbackGrid(`Wordle 1,234 3/6
+
+⬛⬛🟩🟨⬛
+⬛⬛🟩🟩🟩
+🟩🟩🟩🟩🟩
+`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]Knowlege{
+		{Absent, Absent, Full, Present, Absent},
+		{Absent, Absent, Full, Full, Full},
+		{Full, Full, Full, Full, Full},
+	}, grid)
+
+	_, err = ParseFeedbackGrid("BBGYB\nBBGGGG")
+	assert.EqualError(t, err, "line 2 of the grid has 6 letters, the first row has 5")
+
+	_, err = ParseFeedbackGrid("BBGYB\nBBGRG")
+	assert.EqualError(t, err, "line 2 of the grid: unexpected [R] at position 4 in feedback [BBGRG], expected G, Y and B, 2, 1 and 0 or the share emoji")
+
+	_, err = ParseFeedbackGrid("Wordle 1,234 X/6")
+	assert.EqualError(t, err, "the grid has no rows of feedback")
+}
+
+func TestReverseGrid(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"crane", "crate", "trace", "slate", "brick"})
+	require.NoError(t, err)
+
+	grid, err := ParseFeedbackGrid("BBGGG\nGGGGG")
+	require.NoError(t, err)
+
+	matches, err := db.ReverseGrid(grid)
+	require.NoError(t, err)
+	assert.Equal(t, []GridMatch{
+		{Answer: "crate", Rows: [][]string{{"slate"}, {"crate"}}},
+		{Answer: "slate", Rows: [][]string{{"crate"}, {"slate"}}},
+	}, matches)
+
+	grid, err = ParseFeedbackGrid("GGGGG\nBBGGG")
+	require.NoError(t, err)
+
+	_, err = db.ReverseGrid(grid)
+	assert.EqualError(t, err, "row 1 of the grid is solved but is not the last row")
+}
+
+func TestReverseGridAgreesWithFeedback(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words[:3000])
+	require.NoError(t, err)
+
+	answer := db.PickRandomWordOfLength(5)
+	var grid [][]Knowlege
+	for _, guess := range []string{db.PickRandomWordOfLength(5), db.PickRandomWordOfLength(5), answer} {
+		grid = append(grid, BuildKnowledgeForGuess(answer, guess))
+	}
+
+	matches, err := db.ReverseGrid(grid)
+	require.NoError(t, err)
+
+	var found bool
+	for _, m := range matches {
+		found = found || m.Answer == answer
+		for r, guesses := range m.Rows {
+			require.NotEmpty(t, guesses)
+			for _, guess := range guesses {
+				assert.Equal(t, grid[r], BuildKnowledgeForGuess(m.Answer, guess), "%v %v", m.Answer, guess)
+			}
+		}
+	}
+	assert.True(t, found, "the answer %v is consistent with its own grid", answer)
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"github.com/pkg/errors"
+)
+
+const MaxGuesses = 6
+
+// Session holds every guess made against a single wordle so that searches accumulate what was learned on earlier rows
+type Session struct {
+	index   *Index
+	length  int
+	guesses []Wordle
+}
+
+func NewSession(index *Index, length int) *Session {
+	return &Session{
+		index:  index,
+		length: length,
+	}
+}
+
+func (s *Session) Add(guess Wordle) error {
+	return s.add(guess, MaxGuesses)
+}
+
+func (s *Session) add(guess Wordle, maxGuesses int) error {
+	if err := s.accepts(guess, maxGuesses); err != nil {
+		return err
+	}
+
+	s.guesses = append(s.guesses, guess)
+	return nil
+}
+
+// accepts checks a guess could be added without changing the session
+func (s *Session) accepts(guess Wordle, maxGuesses int) error {
+	if len(s.guesses) >= maxGuesses {
+		return errors.Errorf("session already has %d guesses", maxGuesses)
+	}
+
+	if s.Solved() {
+		return errors.New("session is already solved")
+	}
+
+	if guess.Length() != s.length {
+		return errors.Errorf("guess [%v] does not match the session word length of %d", guess.letters, s.length)
+	}
+
+	return nil
+}
+
+func (s *Session) Length() int {
+	return s.length
+}
+
+func (s *Session) Guesses() []Wordle {
+	return s.guesses
+}
+
+func (s *Session) Solved() bool {
+	if len(s.guesses) == 0 {
+		return false
+	}
+
+	return len(s.guesses[len(s.guesses)-1].FullyKnownLetters()) == len(s.guesses[len(s.guesses)-1].letters)
+}
+
+func (s *Session) Constraints() *Constraints {
+	c := NewConstraints(s.length)
+	for _, guess := range s.guesses {
+		c.Add(guess)
+	}
+	return c
+}
+
+func (s *Session) Search() (*MatchResult, error) {
+	results := s.index.OrderByLikelihood(s.index.Filter(s.Constraints()))
+
+	var last Wordle
+	if len(s.guesses) > 0 {
+		last = s.guesses[len(s.guesses)-1]
+	}
+
+	return &MatchResult{
+		Items: results,
+		Guess: last,
+	}, nil
+}
+
+// Undo removes the last guess, so a mistyped row can be corrected
+func (s *Session) Undo() (Wordle, error) {
+	if len(s.guesses) == 0 {
+		return Wordle{}, errors.New("session has no guesses to undo")
+	}
+
+	last := s.guesses[len(s.guesses)-1]
+	s.guesses = s.guesses[:len(s.guesses)-1]
+	return last, nil
+}
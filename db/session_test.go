@@ -0,0 +1,194 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAccumulatesConstraints(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"brick", "crane", "crank", "crate", "trace", "react", "cater"})
+	require.NoError(t, err)
+
+	session := NewSession(db, DefaultWordLength)
+
+	first, err := NewWordleSearch("trace", []Knowlege{Present, Full, Full, Present, Full})
+	require.NoError(t, err)
+	require.NoError(t, session.Add(*first))
+
+	result, err := session.Search()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"crate"}, result.Items)
+
+	second, err := NewWordleSearch("crate", []Knowlege{Full, Full, Full, Full, Full})
+	require.NoError(t, err)
+	require.NoError(t, session.Add(*second))
+
+	assert.True(t, session.Solved())
+	assert.EqualError(t, session.Add(*second), "session is already solved")
+}
+
+func TestSessionMergesEveryRow(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"chunk", "latch", "crane", "tread", "cheap", "clasp"})
+	require.NoError(t, err)
+
+	session := NewSession(db, DefaultWordLength)
+	rows := []struct {
+		letters   string
+		knowledge []Knowlege
+		results   []string
+	}{
+		{"noisy", []Knowlege{Absent, Absent, Absent, Absent, Absent}, []string{"cheap", "latch", "tread"}},
+		{"chalk", []Knowlege{Full, Full, Present, None, None}, []string{"cheap"}},
+	}
+
+	for _, row := range rows {
+		guess, err := NewWordleSearch(row.letters, row.knowledge)
+		require.NoError(t, err)
+		require.NoError(t, session.Add(*guess))
+
+		result, err := session.Search()
+		require.NoError(t, err)
+		assert.Equal(t, row.results, result.Items)
+	}
+
+	c := session.Constraints()
+	assert.Equal(t, map[int]string{0: "c", 1: "h"}, c.Greens())
+	assert.Equal(t, []string{"a", "c", "h"}, c.RequiredLetters())
+	assert.Equal(t, []string{"i", "n", "o", "s", "y"}, c.AbsentLetters())
+	assert.Equal(t, []string{"a", "i"}, c.ExcludedAt(2))
+}
+
+func TestSessionRejectsSeventhGuess(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"chunk"})
+	require.NoError(t, err)
+
+	session := NewSession(db, DefaultWordLength)
+	guess, err := NewWordleSearch("latch", []Knowlege{Absent, Absent, Absent, Full, Full})
+	require.NoError(t, err)
+
+	for i := 0; i < MaxGuesses; i++ {
+		require.NoError(t, session.Add(*guess))
+	}
+
+	assert.EqualError(t, session.Add(*guess), "session already has 6 guesses")
+}
+
+func TestSessionMergesRepeatedLetterCounts(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"those", "chose", "geese", "tense", "these"})
+	require.NoError(t, err)
+
+	session := NewSession(db, DefaultWordLength)
+	for _, guess := range []string{"eerie", "geese"} {
+		w, err := NewWordleSearch(guess, BuildKnowledgeForGuess("those", guess))
+		require.NoError(t, err)
+		require.NoError(t, session.Add(*w))
+	}
+
+	c := session.Constraints()
+	assert.Equal(t, 1, c.MinCount("e"))
+	assert.Equal(t, 1, c.MaxCount("e"))
+	assert.Equal(t, 1, c.MinCount("s"))
+	assert.Equal(t, -1, c.MaxCount("s"))
+	assert.Equal(t, 0, c.MaxCount("g"))
+
+	result, err := session.Search()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chose", "those"}, result.Items)
+}
+
+func TestSessionCompoundsYellowPositionExclusions(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"arise", "raise", "sober", "roast", "birds"})
+	require.NoError(t, err)
+
+	session := NewSession(db, DefaultWordLength)
+	rows := []struct {
+		letters   string
+		knowledge []Knowlege
+		results   []string
+	}{
+		{"rates", []Knowlege{Present, None, None, None, None}, []string{"arise", "birds", "sober"}},
+		{"purer", []Knowlege{None, None, Present, None, None}, []string{"arise", "sober"}},
+		{"lover", []Knowlege{None, None, None, None, Present}, []string{"arise"}},
+	}
+
+	for _, row := range rows {
+		guess, err := NewWordleSearch(row.letters, row.knowledge)
+		require.NoError(t, err)
+		require.NoError(t, session.Add(*guess))
+
+		result, err := session.Search()
+		require.NoError(t, err)
+		assert.Equal(t, row.results, result.Items)
+	}
+
+	c := session.Constraints()
+	assert.Equal(t, []string{"r"}, c.ExcludedAt(0))
+	assert.Equal(t, []string{"r"}, c.ExcludedAt(2))
+	assert.Equal(t, []string{"r"}, c.ExcludedAt(4))
+}
+
+func TestSessionOnlyAcceptsGuessesOfItsLength(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"tram", "team", "crate", "charts", "stream"})
+	require.NoError(t, err)
+
+	session := NewSession(db, 6)
+	result, err := session.Search()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"charts", "stream"}, result.Items)
+
+	short, err := NewWordleSearch("crate", BuildKnowledgeForGuess("charts", "crate"))
+	require.NoError(t, err)
+	assert.EqualError(t, session.Add(*short), "guess [crate] does not match the session word length of 6")
+
+	guess, err := NewWordleSearch("strata", BuildKnowledgeForGuess("charts", "strata"))
+	require.NoError(t, err)
+	require.NoError(t, session.Add(*guess))
+
+	result, err = session.Search()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"charts"}, result.Items)
+}
+
+func TestSessionUndo(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"crane", "crate", "trace"})
+	require.NoError(t, err)
+
+	session := NewSession(db, DefaultWordLength)
+	_, err = session.Undo()
+	assert.EqualError(t, err, "session has no guesses to undo")
+
+	solved, err := NewWordleSearch("crate", []Knowlege{Full, Full, Full, Full, Full})
+	require.NoError(t, err)
+	require.NoError(t, session.Add(*solved))
+	require.True(t, session.Solved())
+
+	undone, err := session.Undo()
+	require.NoError(t, err)
+	assert.Equal(t, *solved, undone)
+	assert.False(t, session.Solved())
+	assert.Empty(t, session.Guesses())
+}
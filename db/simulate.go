@@ -0,0 +1,187 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash"
+	"github.com/pkg/errors"
+)
+
+// Simulator plays a solver against answers it is not told, remembering the guess chosen after each feedback path
+// so that games sharing an opening sequence do not repeat the same search
+type Simulator struct {
+	solver   *Solver
+	opening  string
+	hardMode bool
+	memo     map[string]string
+}
+
+type GameResult struct {
+	Answer  string   `json:"answer"`
+	Guesses []string `json:"guesses"`
+}
+
+func (g GameResult) Turns() int {
+	return len(g.Guesses)
+}
+
+func (g GameResult) Failed() bool {
+	return g.Turns() > MaxGuesses
+}
+
+type SimulationReport struct {
+	Strategy        string        `json:"strategy"`
+	Opening         string        `json:"opening"`
+	HardMode        bool          `json:"hardMode"`
+	Dictionary      string        `json:"dictionary"` // checksum of the answers, to compare runs over the same words
+	Answers         int           `json:"answers"`
+	Distribution    map[int]int   `json:"distribution"`
+	Failures        int           `json:"failures"`
+	FailureRate     float64       `json:"failureRate"`
+	MeanGuesses     float64       `json:"meanGuesses"`
+	Worst           []GameResult  `json:"worst"`
+	DurationSeconds float64       `json:"durationSeconds"`
+	Duration        time.Duration `json:"-"`
+	Games           []GameResult  `json:"-"`
+}
+
+func NewSimulator(solver *Solver, opening string, hardMode bool) *Simulator {
+	return &Simulator{
+		solver:   solver,
+		opening:  opening,
+		hardMode: hardMode,
+		memo:     map[string]string{},
+	}
+}
+
+// Play scores each guess against the answer with BuildKnowledgeForGuess until it is solved, carrying on past the
+// sixth guess so that failures still report how many guesses they needed
+func (s *Simulator) Play(answer string) (GameResult, error) {
+	if len(answer) != len(s.opening) {
+		return GameResult{}, errors.Errorf("answer [%v] is not the same length as the opening [%v]", answer, s.opening)
+	}
+
+	c := NewConstraints(len(answer))
+	var path []Pattern
+	result := GameResult{Answer: answer}
+	for guess := s.opening; len(result.Guesses) < maxTreeDepth; {
+		result.Guesses = append(result.Guesses, guess)
+
+		knowledge := BuildKnowledgeForGuess(answer, guess)
+		p := PatternOf(knowledge)
+		if p.Solved(len(answer)) {
+			return result, nil
+		}
+
+		c.Add(Wordle{letters: guess, knowledge: knowledge})
+		path = append(path, p)
+
+		next, err := s.next(c, treeKey(path, len(answer)))
+		if err != nil {
+			return result, errors.Wrapf(err, "could not solve [%v] after %v", answer, result.Guesses)
+		}
+		guess = next
+	}
+
+	return result, errors.Errorf("could not solve [%v] within %d guesses", answer, maxTreeDepth)
+}
+
+func (s *Simulator) next(c *Constraints, key string) (string, error) {
+	if guess, ok := s.memo[key]; ok {
+		return guess, nil
+	}
+
+	var suggestions []Suggestion
+	var err error
+	if s.hardMode {
+		suggestions, err = s.solver.SuggestHardMode(c, 1)
+	} else {
+		suggestions, err = s.solver.Suggest(c, 1)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s.memo[key] = suggestions[0].Word
+	return suggestions[0].Word, nil
+}
+
+// Run plays every answer and summarises how many guesses were needed, listing the worst games first
+func (s *Simulator) Run(answers []string, worst int) (*SimulationReport, error) {
+	started := time.Now()
+
+	report := &SimulationReport{
+		Strategy:     s.solver.Strategy().Name(),
+		Opening:      s.opening,
+		HardMode:     s.hardMode,
+		Dictionary:   checksum(answers),
+		Answers:      len(answers),
+		Distribution: map[int]int{},
+	}
+
+	total := 0
+	for _, answer := range answers {
+		game, err := s.Play(answer)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Games = append(report.Games, game)
+		report.Distribution[game.Turns()]++
+		total += game.Turns()
+		if game.Failed() {
+			report.Failures++
+		}
+	}
+
+	if len(answers) > 0 {
+		report.MeanGuesses = float64(total) / float64(len(answers))
+		report.FailureRate = float64(report.Failures) / float64(len(answers))
+	}
+
+	report.Worst = append([]GameResult{}, report.Games...)
+	sort.SliceStable(report.Worst, func(i, j int) bool {
+		if report.Worst[i].Turns() != report.Worst[j].Turns() {
+			return report.Worst[i].Turns() > report.Worst[j].Turns()
+		}
+		return report.Worst[i].Answer < report.Worst[j].Answer
+	})
+	if len(report.Worst) > worst {
+		report.Worst = report.Worst[:worst]
+	}
+
+	report.Duration = time.Since(started)
+	report.DurationSeconds = report.Duration.Seconds()
+	return report, nil
+}
+
+func (r *SimulationReport) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("strategy %s, opening %s, hard mode %v, %d answers (%s)\n",
+		r.Strategy, r.Opening, r.HardMode, r.Answers, r.Dictionary))
+
+	var turns []int
+	for t := range r.Distribution {
+		turns = append(turns, t)
+	}
+	sort.Ints(turns)
+	for _, t := range turns {
+		builder.WriteString(fmt.Sprintf("%3d: %d\n", t, r.Distribution[t]))
+	}
+
+	builder.WriteString(fmt.Sprintf("mean %.4f, failures %d (%.2f%%), took %v\n",
+		r.MeanGuesses, r.Failures, r.FailureRate*100, r.Duration.Round(time.Millisecond)))
+	for _, game := range r.Worst {
+		builder.WriteString(fmt.Sprintf("%s: %s\n", game.Answer, strings.Join(game.Guesses, " ")))
+	}
+	return builder.String()
+}
+
+func checksum(words []string) string {
+	sorted := append([]string{}, words...)
+	sort.Strings(sorted)
+	return fmt.Sprintf("%016x", xxhash.Sum64String(strings.Join(sorted, "\n")))
+}
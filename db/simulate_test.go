@@ -0,0 +1,71 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatorReportsEveryAnswer(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	answers := words[:300]
+	db, err := NewIndex(*log, answers)
+	require.NoError(t, err)
+
+	report, err := NewSimulator(NewSolver(db, EntropyStrategy{}), "crane", false).Run(answers, 5)
+	require.NoError(t, err)
+
+	played := 0
+	total := 0
+	for turns, n := range report.Distribution {
+		played += n
+		total += turns * n
+	}
+	assert.Equal(t, len(answers), played)
+	assert.InDelta(t, float64(total)/float64(len(answers)), report.MeanGuesses, 0.000001)
+	assert.Len(t, report.Worst, 5)
+
+	for i, game := range report.Games {
+		assert.Equal(t, answers[i], game.Answer)
+		assert.Equal(t, "crane", game.Guesses[0])
+		assert.Equal(t, game.Answer, game.Guesses[len(game.Guesses)-1])
+		assert.LessOrEqual(t, game.Turns(), report.Worst[0].Turns())
+	}
+}
+
+func TestSimulatorCountsFailures(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	// every guess can only rule out itself so the last answer needs all seven guesses
+	answers := []string{"batch", "catch", "hatch", "latch", "match", "patch", "watch"}
+	db, err := NewIndex(*log, answers)
+	require.NoError(t, err)
+
+	report, err := NewSimulator(NewSolver(db, MinimaxStrategy{}), "batch", true).Run(answers, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1}, report.Distribution)
+	assert.Equal(t, 1, report.Failures)
+	assert.InDelta(t, 1.0/7.0, report.FailureRate, 0.000001)
+	assert.InDelta(t, 4.0, report.MeanGuesses, 0.000001)
+	assert.Equal(t, []GameResult{{Answer: "watch", Guesses: answers}}, report.Worst)
+
+	encoded, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "minimax", decoded["strategy"])
+	assert.Equal(t, true, decoded["hardMode"])
+	assert.Equal(t, map[string]interface{}{"1": 1.0, "2": 1.0, "3": 1.0, "4": 1.0, "5": 1.0, "6": 1.0, "7": 1.0}, decoded["distribution"])
+	assert.Equal(t, checksum([]string{"watch", "patch", "match", "latch", "hatch", "catch", "batch"}), decoded["dictionary"])
+}
@@ -0,0 +1,283 @@
+package db
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type Suggestion struct {
+	Word              string
+	ExpectedBits      float64 // Shannon entropy of the feedback patterns the guess would produce, weighted by the priors
+	ExpectedRemaining float64 // how many candidates are expected to remain after the guess, weighted by the priors
+	WorstCase         int     // the most candidates that can remain after the guess
+	Candidate         bool    // the guess could itself be the wordle
+}
+
+// Solver recommends the next guess by scoring every allowed word in the index against the remaining candidates
+type Solver struct {
+	index    *Index
+	strategy Strategy
+	patterns *PatternMatrix
+}
+
+func NewSolver(index *Index, strategy Strategy) *Solver {
+	return &Solver{
+		index:    index,
+		strategy: strategy,
+	}
+}
+
+func (s *Solver) Strategy() Strategy {
+	return s.strategy
+}
+
+// UsePatternMatrix looks feedback up in a precomputed matrix rather than scoring each guess, words missing from the
+// matrix are still scored
+func (s *Solver) UsePatternMatrix(m *PatternMatrix) {
+	s.patterns = m
+}
+
+/*
+Suggest scores every allowed guess by how its feedback would split the remaining candidates and ranks them with the
+solver's strategy, returning at most limit suggestions (all of them when limit is 0).
+*/
+func (s *Solver) Suggest(c *Constraints, limit int) ([]Suggestion, error) {
+	return s.suggest(c, limit, s.index.Words(c.Length()))
+}
+
+// SuggestHardMode is Suggest restricted to guesses that reuse every green and every revealed letter
+func (s *Solver) SuggestHardMode(c *Constraints, limit int) ([]Suggestion, error) {
+	var guesses []string
+	for _, word := range s.index.Words(c.Length()) {
+		if c.AllowsInHardMode(word) {
+			guesses = append(guesses, word)
+		}
+	}
+	return s.suggest(c, limit, guesses)
+}
+
+func (s *Solver) suggest(c *Constraints, limit int, guesses []string) ([]Suggestion, error) {
+	candidates := s.index.Filter(c)
+	if len(candidates) == 0 {
+		return nil, errors.New("no words match the constraints")
+	}
+
+	return s.Rank(guesses, candidates, limit), nil
+}
+
+/*
+RankCandidates is Rank with every allowed guess of the candidates' length, for candidates found by another engine. It
+stops scoring and returns the context's error once ctx is done.
+*/
+func (s *Solver) RankCandidates(ctx context.Context, candidates []string, limit int) ([]Suggestion, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	return s.rank(ctx, s.index.Words(len(candidates[0])), candidates, limit)
+}
+
+// Rank scores the guesses against an explicit list of candidates and orders them with the solver's strategy
+func (s *Solver) Rank(guesses []string, candidates []string, limit int) []Suggestion {
+	suggestions, _ := s.rank(context.Background(), guesses, candidates, limit) // a background context is never done
+	return suggestions
+}
+
+func (s *Solver) rank(ctx context.Context, guesses []string, candidates []string, limit int) ([]Suggestion, error) {
+	suggestions, err := scoreGuesses(ctx, guesses, candidates, s.weights(candidates), s.patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return s.strategy.Better(suggestions[i], suggestions[j])
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+/*
+SuggestBoards scores every allowed guess against each board's candidates and adds the scores together, as feedback
+on one board reveals nothing about the others. A board down to its last candidate is solved before anything else, so
+that word is ranked first.
+*/
+func (s *Solver) SuggestBoards(boards []*Constraints, limit int) ([]Suggestion, error) {
+	if len(boards) == 0 {
+		return nil, errors.New("no boards to suggest a guess for")
+	}
+
+	guesses := s.index.Words(boards[0].Length())
+	combined := make([]Suggestion, len(guesses))
+	for i, guess := range guesses {
+		combined[i].Word = guess
+	}
+
+	forced := map[string]bool{}
+	for b, c := range boards {
+		candidates := s.index.Filter(c)
+		if len(candidates) == 0 {
+			return nil, errors.Errorf("no words match the constraints of board %d", b+1)
+		}
+		if len(candidates) == 1 {
+			forced[candidates[0]] = true
+		}
+
+		scores, _ := scoreGuesses(context.Background(), guesses, candidates, s.weights(candidates), s.patterns)
+		for i, score := range scores {
+			combined[i].ExpectedBits += score.ExpectedBits
+			combined[i].ExpectedRemaining += score.ExpectedRemaining
+			combined[i].WorstCase += score.WorstCase
+			combined[i].Candidate = combined[i].Candidate || score.Candidate
+		}
+	}
+
+	sort.Slice(combined, func(i, j int) bool {
+		a, b := combined[i], combined[j]
+		if forced[a.Word] != forced[b.Word] {
+			return forced[a.Word]
+		}
+		return s.strategy.Better(a, b)
+	})
+
+	if limit > 0 && len(combined) > limit {
+		combined = combined[:limit]
+	}
+	return combined, nil
+}
+
+func (s *Solver) weights(candidates []string) []float64 {
+	if s.index.priors == nil {
+		return nil
+	}
+
+	weights := make([]float64, len(candidates))
+	for i, c := range candidates {
+		weights[i] = s.index.Prior(c)
+	}
+	return weights
+}
+
+type bucket struct {
+	size   int
+	weight float64
+}
+
+// scoreGuesses weights each candidate by its prior, or equally when weights is nil, and gives up once ctx is done
+func scoreGuesses(ctx context.Context, guesses []string, candidates []string, weights []float64, m *PatternMatrix) ([]Suggestion, error) {
+	isCandidate := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		isCandidate[c] = true
+	}
+
+	if weights == nil {
+		weights = make([]float64, len(candidates))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+
+	answerIDs := make([]int, len(candidates))
+	for i, c := range candidates {
+		answerIDs[i] = -1
+		if m != nil {
+			if id, ok := m.answerIDs[c]; ok {
+				answerIDs[i] = id
+			}
+		}
+	}
+
+	suggestions := make([]Suggestion, len(guesses))
+	work := make(chan int, len(guesses))
+	for i := range guesses {
+		work <- i
+	}
+	close(work)
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buckets := map[Pattern]bucket{}
+			for i := range work {
+				if ctx.Err() != nil {
+					return
+				}
+				guess := guesses[i]
+				for p := range buckets {
+					delete(buckets, p)
+				}
+				guessID, ok := -1, false
+				if m != nil {
+					guessID, ok = m.guessIDs[guess]
+				}
+
+				for c, candidate := range candidates {
+					var p Pattern
+					if ok && answerIDs[c] >= 0 {
+						p = m.Pattern(guessID, answerIDs[c])
+					} else {
+						p = FeedbackPattern(candidate, guess)
+					}
+					b := buckets[p]
+					b.size++
+					b.weight += weights[c]
+					buckets[p] = b
+				}
+
+				bits, remaining, worst := bucketStats(buckets, total)
+				suggestions[i] = Suggestion{
+					Word:              guess,
+					ExpectedBits:      bits,
+					ExpectedRemaining: remaining,
+					WorstCase:         worst,
+					Candidate:         isCandidate[guess],
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// bucketStats sums the buckets lightest first so that the scores do not depend on map iteration order
+func bucketStats(buckets map[Pattern]bucket, total float64) (float64, float64, int) {
+	sorted := make([]bucket, 0, len(buckets))
+	for _, b := range buckets {
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].weight != sorted[j].weight {
+			return sorted[i].weight < sorted[j].weight
+		}
+		return sorted[i].size < sorted[j].size
+	})
+
+	var bits, remaining float64
+	var worst int
+	for _, b := range sorted {
+		p := b.weight / total
+		bits -= p * math.Log2(p)
+		remaining += p * float64(b.size)
+		if b.size > worst {
+			worst = b.size
+		}
+	}
+	return bits, remaining, worst
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolverRanksGuessesByExpectedInformation(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"batch", "catch", "hatch", "latch", "match", "patch", "watch", "clomp"})
+	require.NoError(t, err)
+
+	c := NewConstraints(5)
+	guess, err := NewWordleSearch("latch", []Knowlege{Absent, Full, Full, Full, Full})
+	require.NoError(t, err)
+	c.Add(*guess)
+
+	suggestions, err := NewSolver(db, EntropyStrategy{}).Suggest(c, 3)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 3)
+
+	// clomp splits c, m and p away from the rest, every other guess only identifies itself
+	assert.Equal(t, "clomp", suggestions[0].Word)
+	assert.False(t, suggestions[0].Candidate)
+	assert.InDelta(t, 1.7925, suggestions[0].ExpectedBits, 0.0001)
+	assert.InDelta(t, 2.0, suggestions[0].ExpectedRemaining, 0.0001)
+	assert.Equal(t, 3, suggestions[0].WorstCase)
+
+	assert.Equal(t, "batch", suggestions[1].Word)
+	assert.True(t, suggestions[1].Candidate)
+	assert.InDelta(t, 0.6500, suggestions[1].ExpectedBits, 0.0001)
+	assert.InDelta(t, 4.3333, suggestions[1].ExpectedRemaining, 0.0001)
+
+	ranked, err := NewSolver(db, EntropyStrategy{}).RankCandidates(context.Background(), db.Filter(c), 3)
+	require.NoError(t, err)
+	assert.Equal(t, suggestions, ranked)
+
+	ranked, err = NewSolver(db, EntropyStrategy{}).RankCandidates(context.Background(), nil, 3)
+	require.NoError(t, err)
+	assert.Empty(t, ranked)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewSolver(db, EntropyStrategy{}).RankCandidates(cancelled, db.Filter(c), 3)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSolverPrefersTheLastCandidate(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, []string{"crane", "trace", "slate"})
+	require.NoError(t, err)
+
+	c := NewConstraints(5)
+	guess, err := NewWordleSearch("slate", BuildKnowledgeForGuess("trace", "slate"))
+	require.NoError(t, err)
+	c.Add(*guess)
+
+	suggestions, err := NewSolver(db, EntropyStrategy{}).Suggest(c, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "trace", suggestions[0].Word)
+	assert.Len(t, suggestions, 3)
+
+	c.Add(Wordle{letters: "crane", knowledge: []Knowlege{Full, Full, Full, Full, Full}})
+	_, err = NewSolver(db, EntropyStrategy{}).Suggest(c, 0)
+	assert.EqualError(t, err, "no words match the constraints")
+}
+
+func TestMinimaxStrategyMinimisesTheLargestBucket(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	db, err := NewIndex(*log, words)
+	require.NoError(t, err)
+
+	c := NewConstraints(5)
+	guess, err := NewWordleSearch("crane", BuildKnowledgeForGuess("latch", "crane"))
+	require.NoError(t, err)
+	c.Add(*guess)
+
+	entropy, err := NewSolver(db, EntropyStrategy{}).Suggest(c, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "talis", entropy[0].Word)
+	assert.Equal(t, 30, entropy[0].WorstCase)
+
+	minimax, err := NewSolver(db, MinimaxStrategy{}).Suggest(c, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "tacos", minimax[0].Word)
+	assert.Equal(t, 19, minimax[0].WorstCase)
+	assert.Less(t, minimax[0].ExpectedBits, entropy[0].ExpectedBits)
+
+	for i := 1; i < len(minimax); i++ {
+		assert.LessOrEqual(t, minimax[i-1].WorstCase, minimax[i].WorstCase)
+	}
+}
+
+func TestStrategyNamed(t *testing.T) {
+	s, err := StrategyNamed("entropy")
+	require.NoError(t, err)
+	assert.Equal(t, EntropyStrategyName, s.Name())
+
+	_, err = StrategyNamed("random")
+	assert.EqualError(t, err, "unknown strategy [random], expected one of [entropy minimax]")
+}
+
+func TestSolverWeightsCandidatesByPrior(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	db, err := NewIndexWithAnswers(*log, []string{"clomp"}, []string{"batch", "catch", "hatch", "match", "patch", "watch"})
+	require.NoError(t, err)
+
+	solver := NewSolver(db, EntropyStrategy{})
+	suggestions, err := solver.Suggest(NewConstraints(5), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "clomp", suggestions[0].Word)
+
+	// when batch is by far the most likely answer, guessing it is worth more than splitting the unlikely words
+	db.SetPriors(map[string]float64{"batch": 1000})
+	suggestions, err = solver.Suggest(NewConstraints(5), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "batch", suggestions[0].Word)
+	assert.InDelta(t, (1001.0*1+5*5)/1006, suggestions[0].ExpectedRemaining, 0.0001)
+}
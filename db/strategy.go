@@ -0,0 +1,77 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Strategy decides which of two scored guesses is better, so the same Solver can rank guesses in different ways
+type Strategy interface {
+	Name() string
+	Better(a Suggestion, b Suggestion) bool
+}
+
+const (
+	EntropyStrategyName = "entropy"
+	MinimaxStrategyName = "minimax"
+)
+
+var Strategies = map[string]Strategy{
+	EntropyStrategyName: EntropyStrategy{},
+	MinimaxStrategyName: MinimaxStrategy{},
+}
+
+func StrategyNamed(name string) (Strategy, error) {
+	if s, ok := Strategies[name]; ok {
+		return s, nil
+	}
+	return nil, errors.Errorf("unknown strategy [%v], expected one of %v", name, StrategyNames())
+}
+
+func StrategyNames() []string {
+	var names []string
+	for name := range Strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EntropyStrategy prefers the guess whose feedback reveals the most information on average
+type EntropyStrategy struct{}
+
+func (EntropyStrategy) Name() string {
+	return EntropyStrategyName
+}
+
+func (EntropyStrategy) Better(a Suggestion, b Suggestion) bool {
+	if a.ExpectedBits != b.ExpectedBits {
+		return a.ExpectedBits > b.ExpectedBits
+	}
+	return tieBreak(a, b)
+}
+
+// MinimaxStrategy prefers the guess whose worst feedback leaves the fewest candidates, then the fewest on average
+type MinimaxStrategy struct{}
+
+func (MinimaxStrategy) Name() string {
+	return MinimaxStrategyName
+}
+
+func (MinimaxStrategy) Better(a Suggestion, b Suggestion) bool {
+	if a.WorstCase != b.WorstCase {
+		return a.WorstCase < b.WorstCase
+	}
+	if a.ExpectedRemaining != b.ExpectedRemaining {
+		return a.ExpectedRemaining < b.ExpectedRemaining
+	}
+	return tieBreak(a, b)
+}
+
+func tieBreak(a Suggestion, b Suggestion) bool {
+	if a.Candidate != b.Candidate {
+		return a.Candidate
+	}
+	return a.Word < b.Word
+}
@@ -0,0 +1,233 @@
+package db
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	treeFormatVersion = "wordle-decision-tree v1"
+	maxTreeDepth      = 32
+)
+
+// DecisionTree is a precomputed solving strategy, it maps the feedback received so far to the next guess to make
+type DecisionTree struct {
+	Opening        string
+	Length         int
+	Strategy       string
+	Answers        int
+	MaxDepth       int     // the most guesses needed to solve any answer, including the opening
+	AverageGuesses float64 // the mean number of guesses needed across every answer
+	nodes          map[string]string
+}
+
+/*
+BuildDecisionTree plays the opening against every answer and, for each feedback pattern, picks the next guess with
+the solver's strategy until every answer is solved. When guesses is empty the next guess is always chosen from the
+answers that remain, otherwise it is chosen from guesses.
+*/
+func BuildDecisionTree(solver *Solver, opening string, answers []string, guesses []string) (*DecisionTree, error) {
+	for _, answer := range answers {
+		if len(answer) != len(opening) {
+			return nil, errors.Errorf("answer [%v] is not the same length as the opening [%v]", answer, opening)
+		}
+	}
+
+	b := treeBuilder{
+		solver:  solver,
+		guesses: guesses,
+		tree: &DecisionTree{
+			Opening:  opening,
+			Length:   len(opening),
+			Strategy: solver.Strategy().Name(),
+			Answers:  len(answers),
+			nodes:    map[string]string{},
+		},
+	}
+
+	if err := b.expand(nil, opening, answers, 1); err != nil {
+		return nil, err
+	}
+
+	if len(answers) > 0 {
+		b.tree.AverageGuesses = float64(b.totalGuesses) / float64(len(answers))
+	}
+	return b.tree, nil
+}
+
+type treeBuilder struct {
+	solver       *Solver
+	guesses      []string
+	tree         *DecisionTree
+	totalGuesses int
+}
+
+func (b *treeBuilder) expand(path []Pattern, guess string, candidates []string, depth int) error {
+	if depth > maxTreeDepth {
+		return errors.Errorf("could not separate %v within %d guesses", candidates, maxTreeDepth)
+	}
+
+	buckets := map[Pattern][]string{}
+	for _, candidate := range candidates {
+		p := FeedbackPattern(candidate, guess)
+		buckets[p] = append(buckets[p], candidate)
+	}
+
+	patterns := make([]Pattern, 0, len(buckets))
+	for p := range buckets {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i] < patterns[j] })
+
+	for _, p := range patterns {
+		bucket := buckets[p]
+		if p.Solved(len(guess)) {
+			b.totalGuesses += depth
+			if depth > b.tree.MaxDepth {
+				b.tree.MaxDepth = depth
+			}
+			continue
+		}
+
+		next := b.choose(bucket)
+		branch := append(append([]Pattern{}, path...), p)
+		b.tree.nodes[treeKey(branch, len(guess))] = next
+		if err := b.expand(branch, next, bucket, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *treeBuilder) choose(candidates []string) string {
+	if len(candidates) <= 2 {
+		return candidates[0]
+	}
+
+	guesses := b.guesses
+	if len(guesses) == 0 {
+		guesses = candidates
+	}
+
+	best := b.solver.Rank(guesses, candidates, 1)[0]
+	if best.WorstCase == len(candidates) && !best.Candidate {
+		return candidates[0] // the guess would tell us nothing, so at least try to win
+	}
+	return best.Word
+}
+
+// Next is the guess to make after receiving the given feedback, starting with the feedback for the opening
+func (t *DecisionTree) Next(feedback []Pattern) (string, bool) {
+	if len(feedback) == 0 {
+		return t.Opening, true
+	}
+	guess, ok := t.nodes[treeKey(feedback, t.Length)]
+	return guess, ok
+}
+
+func (t *DecisionTree) Size() int {
+	return len(t.nodes)
+}
+
+func treeKey(path []Pattern, length int) string {
+	digits := make([]string, len(path))
+	for i, p := range path {
+		digits[i] = p.Digits(length)
+	}
+	return strings.Join(digits, ",")
+}
+
+/*
+WriteTo serialises the tree as text, a header of "name value" lines followed by a blank line and then one
+"feedback<TAB>guess" line per node ordered by feedback, where feedback is the comma separated digits of every pattern
+received since the opening.
+*/
+func (t *DecisionTree) WriteTo(w io.Writer) (int64, error) {
+	var builder strings.Builder
+	builder.WriteString(treeFormatVersion + "\n")
+	builder.WriteString(fmt.Sprintf("opening %s\n", t.Opening))
+	builder.WriteString(fmt.Sprintf("length %d\n", t.Length))
+	builder.WriteString(fmt.Sprintf("strategy %s\n", t.Strategy))
+	builder.WriteString(fmt.Sprintf("answers %d\n", t.Answers))
+	builder.WriteString(fmt.Sprintf("max-depth %d\n", t.MaxDepth))
+	builder.WriteString(fmt.Sprintf("average-guesses %s\n", strconv.FormatFloat(t.AverageGuesses, 'f', -1, 64)))
+	builder.WriteString("\n")
+
+	keys := make([]string, 0, len(t.nodes))
+	for k := range t.nodes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		builder.WriteString(k + "\t" + t.nodes[k] + "\n")
+	}
+
+	n, err := io.WriteString(w, builder.String())
+	return int64(n), err
+}
+
+func LoadDecisionTree(r io.Reader) (*DecisionTree, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+
+	if !scanner.Scan() || scanner.Text() != treeFormatVersion {
+		return nil, errors.Errorf("expected the tree to start with [%v]", treeFormatVersion)
+	}
+
+	t := &DecisionTree{nodes: map[string]string{}}
+	line := 1
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if text == "" {
+			break
+		}
+
+		name, value, found := strings.Cut(text, " ")
+		if !found {
+			return nil, errors.Errorf("line %d: expected a header of the form [name value] but was [%v]", line, text)
+		}
+
+		var err error
+		switch name {
+		case "opening":
+			t.Opening = value
+		case "length":
+			t.Length, err = strconv.Atoi(value)
+		case "strategy":
+			t.Strategy = value
+		case "answers":
+			t.Answers, err = strconv.Atoi(value)
+		case "max-depth":
+			t.MaxDepth, err = strconv.Atoi(value)
+		case "average-guesses":
+			t.AverageGuesses, err = strconv.ParseFloat(value, 64)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid %v", line, name)
+		}
+	}
+
+	if t.Opening == "" || t.Length != len(t.Opening) {
+		return nil, errors.New("the tree header must contain an opening and its length")
+	}
+
+	for scanner.Scan() {
+		line++
+		key, guess, found := strings.Cut(scanner.Text(), "\t")
+		if !found || len(guess) != t.Length {
+			return nil, errors.Errorf("line %d: expected [feedback<TAB>guess] but was [%v]", line, scanner.Text())
+		}
+		t.nodes[key] = guess
+	}
+
+	return t, scanner.Err()
+}
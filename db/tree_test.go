@@ -0,0 +1,95 @@
+package db
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecisionTreeSolvesEveryAnswer(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	words, err := loadWords("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	answers := words[:600]
+	db, err := NewIndex(*log, answers)
+	require.NoError(t, err)
+
+	tree, err := BuildDecisionTree(NewSolver(db, EntropyStrategy{}), "crane", answers, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(answers), tree.Answers)
+	assert.Equal(t, EntropyStrategyName, tree.Strategy)
+
+	total := 0
+	for _, answer := range answers {
+		guesses := playTree(t, tree, answer)
+		assert.LessOrEqual(t, guesses, tree.MaxDepth, answer)
+		total += guesses
+	}
+	assert.InDelta(t, float64(total)/float64(len(answers)), tree.AverageGuesses, 0.000001)
+}
+
+func TestDecisionTreeRoundTrip(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	answers := []string{"batch", "catch", "hatch", "latch", "match", "patch", "watch", "crane", "slate"}
+	db, err := NewIndex(*log, answers)
+	require.NoError(t, err)
+
+	tree, err := BuildDecisionTree(NewSolver(db, MinimaxStrategy{}), "clomp", answers, answers)
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	_, err = tree.WriteTo(&buffer)
+	require.NoError(t, err)
+
+	loaded, err := LoadDecisionTree(strings.NewReader(buffer.String()))
+	require.NoError(t, err)
+	assert.Equal(t, tree, loaded)
+
+	var again bytes.Buffer
+	_, err = loaded.WriteTo(&again)
+	require.NoError(t, err)
+	assert.Equal(t, buffer.String(), again.String())
+
+	for _, answer := range answers {
+		playTree(t, loaded, answer)
+	}
+}
+
+func TestLoadDecisionTreeRejectsMalformedFiles(t *testing.T) {
+	_, err := LoadDecisionTree(strings.NewReader("not a tree\n"))
+	assert.EqualError(t, err, "expected the tree to start with [wordle-decision-tree v1]")
+
+	_, err = LoadDecisionTree(strings.NewReader("wordle-decision-tree v1\nopening crane\nlength five\n"))
+	assert.EqualError(t, err, `line 3: invalid length: strconv.Atoi: parsing "five": invalid syntax`)
+
+	_, err = LoadDecisionTree(strings.NewReader("wordle-decision-tree v1\nopening crane\nlength 5\n\n00000 slate\n"))
+	assert.EqualError(t, err, "line 5: expected [feedback<TAB>guess] but was [00000 slate]")
+}
+
+func playTree(t *testing.T, tree *DecisionTree, answer string) int {
+	t.Helper()
+	var feedback []Pattern
+	for guesses := 1; guesses <= maxTreeDepth; guesses++ {
+		guess, ok := tree.Next(feedback)
+		require.True(t, ok, "no guess for %v after %v", answer, feedback)
+
+		p := FeedbackPattern(answer, guess)
+		if p.Solved(len(answer)) {
+			return guesses
+		}
+		feedback = append(feedback, p)
+	}
+
+	t.Fatalf("%v was never solved", answer)
+	return 0
+}
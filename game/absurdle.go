@@ -0,0 +1,127 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+/*
+Absurdle is an adversarial game with no fixed hidden word. Each guess is scored with the feedback that keeps the
+most answers in play, so the game only commits to a word once every other choice would leave fewer candidates.
+Ties are broken by avoiding a solved pattern and then by the seed, so the same seed and guesses always play out the
+same way. There is no limit on the number of guesses.
+*/
+type Absurdle struct {
+	index      *db.Index
+	length     int
+	candidates []string
+	guesses    []db.Wordle
+	random     *rand.Rand
+}
+
+func NewAbsurdle(index *db.Index, length int, seed int64) (*Absurdle, error) {
+	candidates := index.Answers(length)
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("the index has no answers of length %d", length)
+	}
+
+	return &Absurdle{
+		index:      index,
+		length:     length,
+		candidates: candidates,
+		random:     rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+func (a *Absurdle) Guess(word string) (*db.Wordle, error) {
+	if a.Solved() {
+		return nil, errors.New("the game is already solved")
+	}
+
+	guess, err := validateGuess(a.index, a.length, word)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := map[db.Pattern][]string{}
+	for _, c := range a.candidates {
+		p := db.FeedbackPattern(c, guess)
+		buckets[p] = append(buckets[p], c)
+	}
+
+	p := a.choose(buckets)
+	wordle, err := db.NewWordleSearch(guess, p.Knowledge(a.length))
+	if err != nil {
+		return nil, err
+	}
+
+	a.candidates = buckets[p]
+	a.guesses = append(a.guesses, *wordle)
+	return wordle, nil
+}
+
+// choose picks the largest bucket, preferring one that does not solve the game and then one picked by the seed
+func (a *Absurdle) choose(buckets map[db.Pattern][]string) db.Pattern {
+	patterns := make([]db.Pattern, 0, len(buckets))
+	for p := range buckets {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		return patterns[i] < patterns[j]
+	})
+
+	solved := db.SolvedPattern(a.length)
+	var best []db.Pattern
+	for _, p := range patterns {
+		if len(best) == 0 {
+			best = append(best, p)
+			continue
+		}
+
+		size, bestSize := len(buckets[p]), len(buckets[best[0]])
+		switch {
+		case size > bestSize || size == bestSize && best[0] == solved:
+			best = []db.Pattern{p}
+		case size == bestSize && p != solved:
+			best = append(best, p)
+		}
+	}
+	return best[a.random.Intn(len(best))]
+}
+
+// Candidates lists the answers the game has not yet ruled out
+func (a *Absurdle) Candidates() []string {
+	return append([]string{}, a.candidates...)
+}
+
+// Answer is the word the game was forced to commit to, empty until it is solved
+func (a *Absurdle) Answer() string {
+	if !a.Solved() {
+		return ""
+	}
+	return a.candidates[0]
+}
+
+func (a *Absurdle) Guesses() []db.Wordle {
+	return append([]db.Wordle{}, a.guesses...)
+}
+
+func (a *Absurdle) Length() int {
+	return a.length
+}
+
+// Turns is how many guesses the player needed, or has made so far
+func (a *Absurdle) Turns() int {
+	return len(a.guesses)
+}
+
+func (a *Absurdle) Solved() bool {
+	return solved(a.guesses)
+}
+
+func (a *Absurdle) Over() bool {
+	return a.Solved()
+}
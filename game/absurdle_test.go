@@ -0,0 +1,128 @@
+package game
+
+import (
+	"bufio"
+	"os"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsurdleKeepsTheLargestBucket(t *testing.T) {
+	index := loadIndex(t)
+
+	absurdle, err := NewAbsurdle(index, 5, 1)
+	require.NoError(t, err)
+
+	largest := map[db.Pattern]int{}
+	for _, c := range index.Answers(5) {
+		largest[db.FeedbackPattern(c, "crane")]++
+	}
+	var expected int
+	for _, n := range largest {
+		if n > expected {
+			expected = n
+		}
+	}
+
+	w, err := absurdle.Guess("crane")
+	require.NoError(t, err)
+	assert.Len(t, absurdle.Candidates(), expected)
+	assert.Equal(t, expected, largest[db.PatternOf(w.Knowledge())])
+
+	for _, c := range absurdle.Candidates() {
+		assert.Equal(t, w.Knowledge(), db.BuildKnowledgeForGuess(c, "crane"), c)
+	}
+}
+
+func TestAbsurdleOnlyCommitsWhenForced(t *testing.T) {
+	index := smallIndex(t, []string{"crane", "crate", "trace", "slate", "brick", "flock", "plumb"})
+
+	absurdle, err := NewAbsurdle(index, 5, 7)
+	require.NoError(t, err)
+
+	w, err := absurdle.Guess("crane")
+	require.NoError(t, err)
+	assert.False(t, absurdle.Solved(), "every bucket holds one answer so the game avoids the solved one")
+	assert.NotEqual(t, "GGGGG", w.Feedback(db.LetterFeedback))
+	require.Len(t, absurdle.Candidates(), 1)
+	assert.Empty(t, absurdle.Answer())
+
+	answer := absurdle.Candidates()[0]
+	_, err = absurdle.Guess(answer)
+	require.NoError(t, err)
+
+	assert.True(t, absurdle.Solved())
+	assert.True(t, absurdle.Over())
+	assert.Equal(t, answer, absurdle.Answer())
+	assert.Equal(t, 2, absurdle.Turns())
+
+	_, err = absurdle.Guess(answer)
+	assert.EqualError(t, err, "the game is already solved")
+}
+
+func TestAbsurdleIsDeterministicUnderASeed(t *testing.T) {
+	index := loadIndex(t)
+
+	play := func(seed int64) []string {
+		absurdle, err := NewAbsurdle(index, 5, seed)
+		require.NoError(t, err)
+
+		var feedback []string
+		for _, guess := range []string{"crane", "tumpy", "doilt", "bogus"} {
+			w, err := absurdle.Guess(guess)
+			require.NoError(t, err)
+			feedback = append(feedback, w.Feedback(db.DigitFeedback))
+		}
+		return append(feedback, absurdle.Candidates()...)
+	}
+
+	assert.Equal(t, play(42), play(42))
+}
+
+func TestAbsurdleRejectsInvalidGuesses(t *testing.T) {
+	index := smallIndex(t, []string{"crane", "crate", "trace"})
+
+	absurdle, err := NewAbsurdle(index, 5, 1)
+	require.NoError(t, err)
+
+	_, err = absurdle.Guess("cranes")
+	assert.EqualError(t, err, "guess [cranes] must have 5 letters")
+
+	_, err = absurdle.Guess("zzzzz")
+	assert.EqualError(t, err, "guess [zzzzz] is not in the dictionary")
+	assert.Equal(t, 0, absurdle.Turns())
+
+	_, err = NewAbsurdle(index, 6, 1)
+	assert.EqualError(t, err, "the index has no answers of length 6")
+}
+
+func smallIndex(t *testing.T, words []string) *db.Index {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	index, err := db.NewIndex(*log, words)
+	require.NoError(t, err)
+	return index
+}
+
+func loadIndex(t *testing.T) *db.Index {
+	file, err := os.Open("../cmd/search/dictionary.txt")
+	require.NoError(t, err)
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(file)
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		words = append(words, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+
+	return smallIndex(t, words)
+}
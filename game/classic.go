@@ -0,0 +1,94 @@
+package game
+
+import (
+	"time"
+
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+// Classic is the normal game: one hidden answer, MaxGuesses guesses and every guess must be in the dictionary
+type Classic struct {
+	index   *db.Index
+	answer  string
+	guesses []db.Wordle
+}
+
+func NewClassic(index *db.Index, answer string) (*Classic, error) {
+	if !index.IsAnswer(answer) {
+		return nil, errors.Errorf("[%s] is not one of the answers in the index", answer)
+	}
+
+	return &Classic{
+		index:  index,
+		answer: answer,
+	}, nil
+}
+
+// NewDaily starts the game for the answer of the day, see DailyAnswer
+func NewDaily(index *db.Index, length int, date time.Time, seed int64) (*Classic, error) {
+	answer, err := DailyAnswer(index.Answers(length), date, seed)
+	if err != nil {
+		return nil, err
+	}
+	return NewClassic(index, answer)
+}
+
+// Guess scores the guess against the answer, a guess that is rejected does not use up a turn
+func (c *Classic) Guess(word string) (*db.Wordle, error) {
+	if c.Solved() {
+		return nil, errors.New("the game is already solved")
+	}
+	if c.Over() {
+		return nil, errors.Errorf("the game is over, all %d guesses have been used", db.MaxGuesses)
+	}
+
+	guess, err := validateGuess(c.index, len(c.answer), word)
+	if err != nil {
+		return nil, err
+	}
+
+	wordle, err := db.NewWordleSearch(guess, db.BuildKnowledgeForGuess(c.answer, guess))
+	if err != nil {
+		return nil, err
+	}
+
+	c.guesses = append(c.guesses, *wordle)
+	return wordle, nil
+}
+
+// Answer is only revealed once the game is over
+func (c *Classic) Answer() string {
+	if !c.Over() {
+		return ""
+	}
+	return c.answer
+}
+
+func (c *Classic) Guesses() []db.Wordle {
+	return append([]db.Wordle{}, c.guesses...)
+}
+
+func (c *Classic) Length() int {
+	return len(c.answer)
+}
+
+func (c *Classic) Turns() int {
+	return len(c.guesses)
+}
+
+// Remaining is how many guesses the player has left
+func (c *Classic) Remaining() int {
+	if c.Solved() {
+		return 0
+	}
+	return db.MaxGuesses - len(c.guesses)
+}
+
+func (c *Classic) Solved() bool {
+	return solved(c.guesses)
+}
+
+func (c *Classic) Over() bool {
+	return c.Solved() || len(c.guesses) >= db.MaxGuesses
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/howzat/wordle/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassicScoresGuesses(t *testing.T) {
+	index := smallIndex(t, []string{"crane", "crate", "slate", "trace", "eerie"})
+
+	var g Game
+	g, err := NewClassic(index, "crate")
+	require.NoError(t, err)
+
+	w, err := g.Guess("SLATE")
+	require.NoError(t, err)
+	assert.Equal(t, "slate", w.Letters())
+	assert.Equal(t, "BBGGG", w.Feedback(db.LetterFeedback))
+	assert.False(t, g.Over())
+
+	w, err = g.Guess("crate")
+	require.NoError(t, err)
+	assert.Equal(t, "GGGGG", w.Feedback(db.LetterFeedback))
+	assert.True(t, g.Solved())
+	assert.True(t, g.Over())
+	assert.Equal(t, 2, g.Turns())
+
+	_, err = g.Guess("crane")
+	assert.EqualError(t, err, "the game is already solved")
+}
+
+func TestClassicEnforcesSixGuessesAndTheDictionary(t *testing.T) {
+	index := smallIndex(t, []string{"crane", "crate", "slate", "trace"})
+
+	classic, err := NewClassic(index, "crate")
+	require.NoError(t, err)
+
+	_, err = classic.Guess("zzzzz")
+	assert.EqualError(t, err, "guess [zzzzz] is not in the dictionary")
+	_, err = classic.Guess("crates")
+	assert.EqualError(t, err, "guess [crates] must have 5 letters")
+	assert.Equal(t, db.MaxGuesses, classic.Remaining(), "rejected guesses do not use a turn")
+
+	for i := 0; i < db.MaxGuesses; i++ {
+		assert.Empty(t, classic.Answer(), "the answer is hidden until the game is over")
+		_, err = classic.Guess("slate")
+		require.NoError(t, err)
+	}
+
+	assert.True(t, classic.Over())
+	assert.False(t, classic.Solved())
+	assert.Equal(t, 0, classic.Remaining())
+	assert.Equal(t, "crate", classic.Answer())
+
+	_, err = classic.Guess("crate")
+	assert.EqualError(t, err, "the game is over, all 6 guesses have been used")
+
+	_, err = NewClassic(index, "zzzzz")
+	assert.EqualError(t, err, "[zzzzz] is not one of the answers in the index")
+}
+
+func TestKeyboardKeepsTheBestFeedback(t *testing.T) {
+	index := smallIndex(t, []string{"crane", "crate", "slate", "trace", "eerie"})
+
+	classic, err := NewClassic(index, "crate")
+	require.NoError(t, err)
+
+	_, err = classic.Guess("trace")
+	require.NoError(t, err)
+	keyboard := KeyboardOf(classic.Guesses())
+	assert.Equal(t, db.Present, keyboard.State("t"))
+	assert.Equal(t, db.Full, keyboard.State("r"))
+
+	_, err = classic.Guess("slate")
+	require.NoError(t, err)
+	keyboard = KeyboardOf(classic.Guesses())
+	assert.Equal(t, db.Full, keyboard.State("t"), "green replaces yellow")
+	assert.Equal(t, db.Absent, keyboard.State("s"))
+	assert.Equal(t, db.None, keyboard.State("q"))
+
+	_, err = classic.Guess("eerie")
+	require.NoError(t, err)
+	assert.Equal(t, db.Full, KeyboardOf(classic.Guesses()).State("e"), "a later gray for a repeated letter does not hide the green")
+}
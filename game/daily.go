@@ -0,0 +1,40 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Epoch is the date of the first daily game, day 0
+var Epoch = time.Date(2021, time.June, 19, 0, 0, 0, 0, time.UTC)
+
+// DayNumber counts the days from the Epoch to the calendar date in the date's own time zone
+func DayNumber(date time.Time) int {
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(Epoch).Hours() / 24)
+}
+
+/*
+DailyAnswer picks the answer for a date. The answers are shuffled once by the seed and the days walk through the
+shuffle, so every answer is used once before any repeats and the same date, seed and answers always give the same
+word. The order the answers are given in does not matter.
+*/
+func DailyAnswer(answers []string, date time.Time, seed int64) (string, error) {
+	if len(answers) == 0 {
+		return "", errors.New("there are no answers to pick the daily word from")
+	}
+
+	sorted := append([]string{}, answers...)
+	sort.Strings(sorted)
+
+	order := rand.New(rand.NewSource(seed)).Perm(len(sorted))
+	day := DayNumber(date) % len(sorted)
+	if day < 0 {
+		day += len(sorted)
+	}
+	return sorted[order[day]], nil
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDayNumber(t *testing.T) {
+	assert.Equal(t, 0, DayNumber(Epoch))
+	assert.Equal(t, 1, DayNumber(time.Date(2021, time.June, 20, 23, 59, 0, 0, time.UTC)))
+
+	sydney := time.FixedZone("AEST", 10*60*60)
+	assert.Equal(t, 1, DayNumber(time.Date(2021, time.June, 20, 1, 0, 0, 0, sydney)), "the date is read in its own time zone")
+}
+
+func TestDailyAnswerIsDeterministic(t *testing.T) {
+	answers := []string{"crane", "crate", "slate", "trace", "brick", "flock", "plumb"}
+	date := time.Date(2022, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	answer, err := DailyAnswer(answers, date, 7)
+	require.NoError(t, err)
+
+	reversed := make([]string, len(answers))
+	for i, a := range answers {
+		reversed[len(answers)-1-i] = a
+	}
+	again, err := DailyAnswer(reversed, date.Add(10*time.Hour), 7)
+	require.NoError(t, err)
+	assert.Equal(t, answer, again, "the same day, seed and answers give the same word")
+
+	seen := map[string]bool{}
+	for day := 0; day < len(answers); day++ {
+		a, err := DailyAnswer(answers, date.AddDate(0, 0, day), 7)
+		require.NoError(t, err)
+		seen[a] = true
+	}
+	assert.Len(t, seen, len(answers), "no answer repeats before every answer has been used")
+
+	_, err = DailyAnswer(nil, date, 7)
+	assert.EqualError(t, err, "there are no answers to pick the daily word from")
+}
+
+func TestNewDaily(t *testing.T) {
+	index := smallIndex(t, []string{"crane", "crate", "slate", "trace", "cranes"})
+	date := time.Date(2022, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+	daily, err := NewDaily(index, 5, date, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5, daily.Length())
+
+	expected, err := DailyAnswer(index.Answers(5), date, 1)
+	require.NoError(t, err)
+	_, err = daily.Guess(expected)
+	require.NoError(t, err)
+	assert.True(t, daily.Solved())
+	assert.Equal(t, expected, daily.Answer())
+
+	_, err = NewDaily(index, 7, date, 1)
+	assert.EqualError(t, err, "there are no answers to pick the daily word from")
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"strings"
+
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+// Game is a single game of wordle, the player makes a guess and the game scores it against the hidden word
+type Game interface {
+	Guess(word string) (*db.Wordle, error)
+	Guesses() []db.Wordle
+	Length() int
+	Turns() int
+	Solved() bool
+	Over() bool
+}
+
+// validateGuess checks the guess is a word of the right length from the index, returning it in lower case
+func validateGuess(index *db.Index, length int, word string) (string, error) {
+	guess := strings.ToLower(strings.TrimSpace(word))
+	if len(guess) != length {
+		return "", errors.Errorf("guess [%s] must have %d letters", word, length)
+	}
+	if !index.Contains(guess) {
+		return "", errors.Errorf("guess [%s] is not in the dictionary", word)
+	}
+	return guess, nil
+}
+
+func solved(guesses []db.Wordle) bool {
+	if len(guesses) == 0 {
+		return false
+	}
+
+	last := guesses[len(guesses)-1]
+	return db.PatternOf(last.Knowledge()).Solved(last.Length())
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"github.com/howzat/wordle/db"
+)
+
+// KeyboardRows is the layout frontends draw the keyboard in
+var KeyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// Keyboard is the best feedback seen for each letter, letters that have not been guessed are missing
+type Keyboard map[string]db.Knowlege
+
+// KeyboardOf builds the keyboard for the guesses made so far, a letter that was green anywhere stays green
+func KeyboardOf(guesses []db.Wordle) Keyboard {
+	k := Keyboard{}
+	for _, guess := range guesses {
+		letters := guess.Letters()
+		for i, knowledge := range guess.Knowledge() {
+			l := string(letters[i])
+			if current, ok := k[l]; !ok || rank(knowledge) > rank(current) {
+				k[l] = knowledge
+			}
+		}
+	}
+	return k
+}
+
+// State is the letter's feedback, None when it has not been guessed
+func (k Keyboard) State(letter string) db.Knowlege {
+	if knowledge, ok := k[letter]; ok {
+		return knowledge
+	}
+	return db.None
+}
+
+func rank(k db.Knowlege) int {
+	switch k {
+	case db.Full:
+		return 3
+	case db.Present:
+		return 2
+	case db.Absent:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/game"
+)
+
+const (
+	clearScreen  = "\x1b[H\x1b[2J"
+	clearLine    = "\x1b[K"
+	hideCursor   = "\x1b[?25l"
+	ShowCursor   = "\x1b[?25h"
+	reset        = "\x1b[0m"
+	tileWidth    = 4 // three for the letter and a gap
+	minimumWidth = 40
+)
+
+var tileColours = map[db.Knowlege]string{
+	db.Full:    "\x1b[1;30;42m",
+	db.Present: "\x1b[1;30;43m",
+	db.Absent:  "\x1b[1;97;100m",
+	db.None:    "\x1b[1;7m",
+}
+
+const emptyTile = "\x1b[2m · " + reset
+
+// Render redraws the whole screen, lines end in \r\n as the terminal is in raw mode
+func (t *TUI) Render() error {
+	lines := t.lines()
+
+	width := t.board().width()
+	if w := tileWidth * len(game.KeyboardRows[0]); w > width {
+		width = w
+	}
+	if width < minimumWidth {
+		width = minimumWidth
+	}
+
+	var builder strings.Builder
+	builder.WriteString(hideCursor + clearScreen)
+	if t.width < width || t.height < len(lines) {
+		builder.WriteString(fmt.Sprintf("make the terminal at least %dx%d%s\r\n", width, len(lines), clearLine))
+	} else {
+		margin := strings.Repeat(" ", (t.width-width)/2)
+		for _, line := range lines {
+			builder.WriteString(margin + center(line, width) + clearLine + "\r\n")
+		}
+	}
+
+	_, err := t.out.Write([]byte(builder.String()))
+	return err
+}
+
+type board struct {
+	rows   [][]string // the coloured tiles of each row
+	length int
+}
+
+func (b board) width() int {
+	return tileWidth*b.length - 1
+}
+
+func (t *TUI) board() board {
+	b := board{length: t.length}
+	for _, w := range t.guesses() {
+		letters, knowledge := w.Letters(), w.Knowledge()
+		var row []string
+		for i := range knowledge {
+			row = append(row, tile(letters[i], knowledge[i]))
+		}
+		b.rows = append(b.rows, row)
+	}
+
+	if len(b.rows) < db.MaxGuesses && !t.over() {
+		var row []string
+		for i := 0; i < t.length; i++ {
+			switch {
+			case i < len(t.input) && i < len(t.feedback):
+				row = append(row, tile(t.input[i], t.feedback[i]))
+			case i < len(t.input):
+				row = append(row, tile(t.input[i], db.None))
+			default:
+				row = append(row, emptyTile)
+			}
+		}
+		b.rows = append(b.rows, row)
+	}
+
+	for len(b.rows) < db.MaxGuesses {
+		row := make([]string, t.length)
+		for i := range row {
+			row[i] = emptyTile
+		}
+		b.rows = append(b.rows, row)
+	}
+	return b
+}
+
+func (t *TUI) lines() []string {
+	var lines []string
+	lines = append(lines, "\x1b[1mW O R D L E"+reset, "")
+
+	for _, row := range t.board().rows {
+		lines = append(lines, strings.Join(row, " "))
+	}
+	lines = append(lines, "")
+
+	keyboard := game.KeyboardOf(t.guesses())
+	for _, keys := range game.KeyboardRows {
+		var row []string
+		for i := 0; i < len(keys); i++ {
+			if state := keyboard.State(string(keys[i])); state != db.None {
+				row = append(row, tile(keys[i], state))
+			} else {
+				row = append(row, " "+strings.ToUpper(string(keys[i]))+" ")
+			}
+		}
+		lines = append(lines, strings.Join(row, " "))
+	}
+	lines = append(lines, "", t.message, t.status, t.help())
+	return lines
+}
+
+// candidates describes what could still be the answer in Assist mode, filtering and solving are too slow for every redraw
+func (t *TUI) candidates() string {
+	if t.mode != Assist || len(t.session.Guesses()) == 0 || t.session.Solved() {
+		return ""
+	}
+
+	candidates := t.index.OrderByLikelihood(t.index.Filter(t.session.Constraints()))
+	status := plural(len(candidates), "candidate", "candidates")
+	if len(candidates) > 0 && len(candidates) <= 3 {
+		status += ": " + strings.Join(candidates, " ")
+	}
+
+	if t.solver != nil && len(candidates) > 0 {
+		if suggestions, err := t.solver.Suggest(t.session.Constraints(), 1); err == nil {
+			status += ", try " + strings.ToUpper(suggestions[0].Word)
+		}
+	}
+	return status
+}
+
+func (t *TUI) help() string {
+	switch {
+	case t.over():
+		return "ctrl-c to quit"
+	case t.enteringScore:
+		return "type the feedback with g y b, enter to add it"
+	case t.mode == Assist:
+		return "type your guess, enter to give its feedback"
+	}
+	return "type a guess, enter to play it, ctrl-c to quit"
+}
+
+func tile(letter byte, k db.Knowlege) string {
+	return tileColours[k] + " " + strings.ToUpper(string(letter)) + " " + reset
+}
+
+// center pads the line to the width, ignoring the escape codes that take no space on screen
+func center(line string, width int) string {
+	visible := utf8.RuneCountInString(StripANSI(line))
+	if visible >= width {
+		return line
+	}
+	left := (width - visible) / 2
+	return strings.Repeat(" ", left) + line + strings.Repeat(" ", width-visible-left)
+}
+
+// StripANSI removes the escape codes from s, leaving what is shown on screen
+func StripANSI(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == keyEscape && i+1 < len(s) && s[i+1] == '[' {
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			continue
+		}
+		builder.WriteByte(s[i])
+	}
+	return builder.String()
+}
+
+func plural(n int, one string, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, one)
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"io"
+	"strings"
+
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/game"
+	"github.com/pkg/errors"
+)
+
+type Mode int
+
+const (
+	Play   Mode = iota // the TUI hides a word and scores the guesses itself
+	Assist             // the player types the feedback they were given by another game
+)
+
+func ModeNamed(name string) (Mode, error) {
+	switch name {
+	case "play":
+		return Play, nil
+	case "assist":
+		return Assist, nil
+	}
+	return 0, errors.Errorf("unknown mode [%v], expected play or assist", name)
+}
+
+const (
+	keyCtrlC     = 0x03
+	keyBackspace = 0x08
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+/*
+TUI draws the board and keyboard of a game with ANSI escape codes and takes the player's keystrokes. In Play mode it
+scores guesses against a hidden word, in Assist mode each guess is followed by the feedback the player was given
+elsewhere and the TUI shows what could still be the answer. It only writes to out, so it can be driven by tests.
+*/
+type TUI struct {
+	out           io.Writer
+	width, height int
+	mode          Mode
+	index         *db.Index
+	solver        *db.Solver
+	length        int
+	game          *game.Classic // Play mode
+	session       *db.Session   // Assist mode
+	input         []byte
+	feedback      []db.Knowlege
+	enteringScore bool // Assist mode, the guess is typed and its feedback is being typed
+	message       string
+	status        string // Assist mode, what could still be the answer, worked out once per guess
+	pending       []byte // an escape sequence split across reads of the terminal
+}
+
+// NewPlay starts a game against the answer, which should be picked from the index
+func NewPlay(out io.Writer, index *db.Index, answer string) (*TUI, error) {
+	g, err := game.NewClassic(index, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TUI{
+		out:    out,
+		mode:   Play,
+		index:  index,
+		length: len(answer),
+		game:   g,
+	}, nil
+}
+
+// NewAssist solves a game played elsewhere, suggesting the next guess when solver is not nil
+func NewAssist(out io.Writer, index *db.Index, solver *db.Solver, length int) *TUI {
+	return &TUI{
+		out:     out,
+		mode:    Assist,
+		index:   index,
+		solver:  solver,
+		length:  length,
+		session: db.NewSession(index, length),
+	}
+}
+
+// Resize redraws the TUI for the new terminal size
+func (t *TUI) Resize(width int, height int) error {
+	t.width, t.height = width, height
+	return t.Render()
+}
+
+// HandleInput applies the keys read from the terminal and redraws, returning false once the player quits with ctrl-c
+func (t *TUI) HandleInput(keys []byte) (bool, error) {
+	keys, t.pending = append(t.pending, keys...), nil
+	for i := 0; i < len(keys); i++ {
+		switch k := keys[i]; {
+		case k == keyCtrlC:
+			return false, nil
+		case k == keyEscape:
+			end := escapeSequenceEnd(keys, i)
+			if end < 0 {
+				t.pending = append([]byte{}, keys[i:]...)
+				return true, t.Render()
+			}
+			i = end
+		case k == keyBackspace || k == keyDelete:
+			t.backspace()
+		case k == '\r' || k == '\n':
+			t.enter()
+		default:
+			t.typed(k)
+		}
+	}
+	return true, t.Render()
+}
+
+/*
+escapeSequenceEnd finds the last byte of the arrow or function key starting at i, which the TUI has no use for, or -1
+when the terminal has not sent all of it yet. An escape followed by anything else is a bare ESC and is ignored alone.
+*/
+func escapeSequenceEnd(keys []byte, i int) int {
+	if i+1 >= len(keys) {
+		return -1
+	}
+	switch keys[i+1] {
+	case '[':
+		for j := i + 2; j < len(keys); j++ {
+			if keys[j] >= 0x40 && keys[j] <= 0x7e {
+				return j
+			}
+		}
+		return -1
+	case 'O':
+		if i+2 >= len(keys) {
+			return -1
+		}
+		return i + 2
+	}
+	return i
+}
+
+func (t *TUI) typed(k byte) {
+	if t.over() {
+		return
+	}
+
+	if t.enteringScore {
+		if len(t.feedback) < t.length {
+			if knowledge, err := db.ParseFeedback(string(k)); err == nil && knowledge[0] != db.None {
+				t.feedback = append(t.feedback, knowledge[0])
+				t.message = ""
+			}
+		}
+		return
+	}
+
+	if k >= 'A' && k <= 'Z' {
+		k += 'a' - 'A'
+	}
+	if k >= 'a' && k <= 'z' && len(t.input) < t.length {
+		t.input = append(t.input, k)
+		t.message = ""
+	}
+}
+
+func (t *TUI) backspace() {
+	switch {
+	case t.enteringScore && len(t.feedback) > 0:
+		t.feedback = t.feedback[:len(t.feedback)-1]
+	case t.enteringScore:
+		t.enteringScore = false
+	case len(t.input) > 0:
+		t.input = t.input[:len(t.input)-1]
+	}
+}
+
+func (t *TUI) enter() {
+	if t.over() {
+		return
+	}
+	if len(t.input) < t.length {
+		t.message = "not enough letters"
+		return
+	}
+
+	if t.mode == Play {
+		if _, err := t.game.Guess(string(t.input)); err != nil {
+			t.message = err.Error()
+			return
+		}
+		t.input = nil
+		t.message = t.result()
+		return
+	}
+
+	if !t.enteringScore {
+		t.enteringScore = true
+		t.message = ""
+		return
+	}
+	if len(t.feedback) < t.length {
+		t.message = "type the feedback for every letter"
+		return
+	}
+
+	w, err := db.NewWordleSearch(string(t.input), t.feedback)
+	if err == nil {
+		err = t.session.Add(*w)
+	}
+	if err != nil {
+		t.message = err.Error()
+		return
+	}
+
+	t.input, t.feedback, t.enteringScore = nil, nil, false
+	t.message = t.result()
+	t.status = t.candidates()
+}
+
+func (t *TUI) result() string {
+	guesses := t.guesses()
+	switch {
+	case t.solved():
+		return "solved in " + plural(len(guesses), "guess", "guesses")
+	case t.mode == Play && t.game.Over():
+		return "out of guesses, the word was " + strings.ToUpper(t.game.Answer())
+	}
+	return ""
+}
+
+func (t *TUI) guesses() []db.Wordle {
+	if t.mode == Play {
+		return t.game.Guesses()
+	}
+	return t.session.Guesses()
+}
+
+func (t *TUI) solved() bool {
+	if t.mode == Play {
+		return t.game.Solved()
+	}
+	return t.session.Solved()
+}
+
+func (t *TUI) over() bool {
+	if t.mode == Play {
+		return t.game.Over()
+	}
+	return t.session.Solved() || len(t.session.Guesses()) >= db.MaxGuesses
+}
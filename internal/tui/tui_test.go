@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTerminal keeps everything written to it, the last frame being what is on screen
+type fakeTerminal struct {
+	bytes.Buffer
+}
+
+func (f *fakeTerminal) frame() string {
+	frames := strings.Split(f.String(), clearScreen)
+	return frames[len(frames)-1]
+}
+
+func (f *fakeTerminal) screen() []string {
+	var lines []string
+	for _, line := range strings.Split(StripANSI(f.frame()), "\r\n") {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+// shows is true when a line of the screen holds exactly the text, whatever the margin
+func (f *fakeTerminal) shows(text string) bool {
+	for _, line := range f.screen() {
+		if strings.TrimSpace(line) == text {
+			return true
+		}
+	}
+	return false
+}
+
+func testIndex(t *testing.T) *db.Index {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	index, err := db.NewIndex(*log, []string{"brick", "crane", "crate", "flock", "plumb", "slate", "trace"})
+	require.NoError(t, err)
+	return index
+}
+
+func TestPlayScoresGuessesOnTheBoard(t *testing.T) {
+	var terminal fakeTerminal
+	ui, err := NewPlay(&terminal, testIndex(t), "crate")
+	require.NoError(t, err)
+	require.NoError(t, ui.Resize(80, 24))
+
+	more, err := ui.HandleInput([]byte("slate\r"))
+	require.NoError(t, err)
+	assert.True(t, more)
+
+	frame := terminal.frame()
+	assert.Contains(t, frame, tile('s', db.Absent)+" "+tile('l', db.Absent)+" "+tile('a', db.Full))
+	assert.Contains(t, frame, tile('s', db.Absent), "the keyboard shows the letters guessed so far")
+	assert.True(t, terminal.shows("Q   W   E   R   T   Y   U   I   O   P"))
+
+	_, err = ui.HandleInput([]byte("cr"))
+	require.NoError(t, err)
+	assert.Contains(t, terminal.frame(), tile('c', db.None)+" "+tile('r', db.None)+" "+emptyTile)
+
+	_, err = ui.HandleInput([]byte("\x1b[Aate\r"))
+	require.NoError(t, err)
+	assert.True(t, terminal.shows("solved in 2 guesses"))
+	assert.True(t, ui.over())
+
+	more, err = ui.HandleInput([]byte{keyCtrlC})
+	require.NoError(t, err)
+	assert.False(t, more)
+}
+
+func TestPlayReportsInvalidGuesses(t *testing.T) {
+	var terminal fakeTerminal
+	ui, err := NewPlay(&terminal, testIndex(t), "crate")
+	require.NoError(t, err)
+	require.NoError(t, ui.Resize(40, 16))
+
+	_, err = ui.HandleInput([]byte("zzz\r"))
+	require.NoError(t, err)
+	assert.True(t, terminal.shows("not enough letters"))
+
+	_, err = ui.HandleInput([]byte("zz\r"))
+	require.NoError(t, err)
+	assert.Contains(t, StripANSI(terminal.frame()), "guess [zzzzz] is not in the dictionary")
+
+	_, err = ui.HandleInput([]byte{keyDelete, keyDelete, keyDelete, keyDelete, keyDelete})
+	require.NoError(t, err)
+	assert.Empty(t, ui.input)
+	assert.Empty(t, ui.game.Guesses())
+}
+
+func TestAssistTakesTheFeedbackForEachGuess(t *testing.T) {
+	var terminal fakeTerminal
+	ui := NewAssist(&terminal, testIndex(t), db.NewSolver(testIndex(t), db.EntropyStrategy{}), 5)
+	require.NoError(t, ui.Resize(80, 24))
+
+	_, err := ui.HandleInput([]byte("slate\rbbg"))
+	require.NoError(t, err)
+	assert.Contains(t, terminal.frame(), tile('s', db.Absent)+" "+tile('l', db.Absent)+" "+tile('a', db.Full)+" "+tile('t', db.None))
+	assert.Contains(t, StripANSI(terminal.frame()), "type the feedback with g y b, enter to add it")
+
+	_, err = ui.HandleInput([]byte("\r"))
+	require.NoError(t, err)
+	assert.Contains(t, StripANSI(terminal.frame()), "type the feedback for every letter")
+
+	_, err = ui.HandleInput([]byte("gg\r"))
+	require.NoError(t, err)
+	assert.Len(t, ui.session.Guesses(), 1)
+	assert.Contains(t, StripANSI(terminal.frame()), "1 candidate: crate, try CRATE")
+
+	_, err = ui.HandleInput([]byte("crate\r22222\r"))
+	require.NoError(t, err)
+	assert.Contains(t, StripANSI(terminal.frame()), "solved in 2 guesses")
+}
+
+func TestAssistBackspaceReturnsToTheGuess(t *testing.T) {
+	var terminal fakeTerminal
+	ui := NewAssist(&terminal, testIndex(t), nil, 5)
+	require.NoError(t, ui.Resize(80, 24))
+
+	_, err := ui.HandleInput([]byte("slate\rb"))
+	require.NoError(t, err)
+	_, err = ui.HandleInput([]byte{keyDelete, keyDelete, keyDelete})
+	require.NoError(t, err)
+
+	assert.False(t, ui.enteringScore)
+	assert.Equal(t, "slat", string(ui.input))
+}
+
+func TestEscapeSequencesSplitAcrossReadsAreSkipped(t *testing.T) {
+	var terminal fakeTerminal
+	ui, err := NewPlay(&terminal, testIndex(t), "crate")
+	require.NoError(t, err)
+	require.NoError(t, ui.Resize(80, 24))
+
+	more, err := ui.HandleInput([]byte{keyEscape})
+	require.NoError(t, err)
+	assert.True(t, more, "a bare escape does not quit")
+
+	_, err = ui.HandleInput([]byte("[Acr\x1b["))
+	require.NoError(t, err)
+	assert.Equal(t, "cr", string(ui.input))
+
+	_, err = ui.HandleInput([]byte("1;5Da\x1bOBt\x1be"))
+	require.NoError(t, err)
+	assert.Equal(t, "crate", string(ui.input))
+	assert.Empty(t, ui.pending)
+}
+
+func TestAssistWorksOutTheCandidatesOncePerGuess(t *testing.T) {
+	var terminal fakeTerminal
+	ui := NewAssist(&terminal, testIndex(t), nil, 5)
+	require.NoError(t, ui.Resize(80, 24))
+
+	_, err := ui.HandleInput([]byte("flock\rbbbyb\r"))
+	require.NoError(t, err)
+	assert.Equal(t, "2 candidates: crane crate", ui.status)
+
+	_, err = ui.session.Undo()
+	require.NoError(t, err)
+	require.NoError(t, ui.Resize(80, 24))
+	assert.True(t, terminal.shows("2 candidates: crane crate"), "redrawing does not filter again")
+}
+
+func TestResize(t *testing.T) {
+	var terminal fakeTerminal
+	ui, err := NewPlay(&terminal, testIndex(t), "crate")
+	require.NoError(t, err)
+
+	require.NoError(t, ui.Resize(30, 40))
+	assert.Equal(t, []string{"make the terminal at least 40x16", ""}, terminal.screen())
+
+	require.NoError(t, ui.Resize(80, 10))
+	assert.Equal(t, []string{"make the terminal at least 40x16", ""}, terminal.screen())
+
+	require.NoError(t, ui.Resize(60, 16))
+	screen := terminal.screen()
+	assert.Len(t, screen, 17)
+	assert.Equal(t, strings.Repeat(" ", 10)+"              W O R D L E", screen[0])
+	assert.Equal(t, strings.Repeat(" ", 10)+"           ·   ·   ·   ·   ·", screen[2])
+}
+
+func TestModeNamed(t *testing.T) {
+	mode, err := ModeNamed("assist")
+	require.NoError(t, err)
+	assert.Equal(t, Assist, mode)
+
+	_, err = ModeNamed("watch")
+	assert.EqualError(t, err, "unknown mode [watch], expected play or assist")
+}
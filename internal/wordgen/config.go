@@ -9,7 +9,9 @@ import (
 const DictionaryBaseDirKey = "DICTIONARY_DIR"
 
 type Config struct {
-	BaseDir string `env:"DICTIONARY_DIR,required"`
+	BaseDir       string `env:"DICTIONARY_DIR,required"`
+	WordLengths   []int  `env:"WORD_LENGTHS,default=5"`
+	FrequencyFile string `env:"FREQUENCY_FILE"` // optional tab separated word counts used as priors
 }
 
 func NewDictionaryConfig(ctx context.Context) (Config, error) {
@@ -19,6 +19,7 @@ type Consumer struct {
 	AddWordsStream chan Result
 	readWordsLock  sync.Mutex
 	words          []string
+	answers        []string
 	logger         *logr.Logger
 }
 
@@ -31,12 +32,9 @@ func (c *Consumer) Consume(ctx context.Context) {
 	for {
 		select {
 		case event := <-c.AddWordsStream:
-			if event.Err != nil {
-				c.logger.Error(event.Err, "error result received")
-			} else {
-				c.words = append(c.words, event.Words...)
-			}
+			c.add(event)
 		case _ = <-ctx.Done():
+			c.drain()
 			c.readWordsLock.Unlock()
 			return
 		default:
@@ -44,25 +42,58 @@ func (c *Consumer) Consume(ctx context.Context) {
 	}
 }
 
+// drain adds any results the producers sent before the context was cancelled
+func (c *Consumer) drain() {
+	for {
+		select {
+		case event := <-c.AddWordsStream:
+			c.add(event)
+		default:
+			return
+		}
+	}
+}
+
+func (c *Consumer) add(event Result) {
+	if event.Err != nil {
+		c.logger.Error(event.Err, "error result received")
+		return
+	}
+
+	c.words = append(c.words, event.Words...)
+	if event.Tag == AnswerSource {
+		c.answers = append(c.answers, event.Words...)
+	}
+}
+
 func (c *Consumer) ListWords() []string {
 	c.readWordsLock.Lock()
 	defer c.readWordsLock.Unlock()
 	return c.words
 }
 
+// ListAnswers returns the words read from sources tagged as answers, they are also included in ListWords
+func (c *Consumer) ListAnswers() []string {
+	c.readWordsLock.Lock()
+	defer c.readWordsLock.Unlock()
+	return c.answers
+}
+
 type Result struct {
 	Err   error
 	Words []string
+	Tag   SourceTag
 }
 
 func (r *Result) HasError() bool {
 	return r.Err != nil
 }
 
-func Success(w []string) Result {
+func Success(w []string, tag SourceTag) Result {
 	return Result{
 		Err:   nil,
 		Words: w,
+		Tag:   tag,
 	}
 }
 
@@ -14,11 +14,11 @@ func NewProducer(log *logr.Logger, wordsStream chan Result) Producer {
 	}
 }
 
-func (p *Producer) Produce(readWords ReadWordsFn, filterFn FilterFn, mutatorFn MutatorFn) {
+func (p *Producer) Produce(tag SourceTag, readWords ReadWordsFn, filterFn FilterFn, mutatorFn MutatorFn) {
 	words, err := readWords(mutatorFn, filterFn)
 	if err != nil {
 		p.wordsStream <- Failure(err)
 	} else {
-		p.wordsStream <- Success(words)
+		p.wordsStream <- Success(words, tag)
 	}
 }
@@ -6,12 +6,17 @@ import (
 	"io/ioutil"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/howzat/wordle"
 )
 
-var WordleCandidate FilterFn = inOrder(Length(5), Alphabetical(), NoFilter())
+var WordleCandidate FilterFn = WordleCandidateOfLength(5)
+
+func WordleCandidateOfLength(lengths ...int) FilterFn {
+	return inOrder(Lengths(lengths...), Alphabetical(), NoFilter())
+}
 
 func inOrder(fn FilterFn, fns ...FilterFn) FilterFn {
 	if len(fns) == 0 {
@@ -74,6 +79,17 @@ func Length(l int) FilterFn {
 	}
 }
 
+func Lengths(ls ...int) FilterFn {
+	return func(e string) bool {
+		for _, l := range ls {
+			if len(e) == l {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 type ReadWordsFn = func(mutate MutatorFn, filter FilterFn) ([]string, error)
 
 type WordsetFile = map[string]WordsetDictionaryEntry
@@ -133,3 +149,40 @@ func ParseLineSeperatedDictionary(filepath string) ReadWordsFn {
 		return words, nil
 	}
 }
+
+/*
+ParseFrequencies reads a word frequency file of tab separated "word<TAB>count" lines. Counts for words that normalise
+to the same word are summed, and lines that are not a word and a count are skipped.
+*/
+func ParseFrequencies(filepath string, mutate MutatorFn, filter FilterFn) (map[string]float64, error) {
+	fileReader, err := os.Open(filepath)
+	if err != nil {
+		return nil, wordle.WrapErr(err, "error reading file contents [%v]", filepath)
+	}
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(fileReader)
+
+	scanner := bufio.NewScanner(fileReader)
+	scanner.Split(bufio.ScanLines)
+	frequencies := map[string]float64{}
+	for scanner.Scan() {
+		word, count, found := strings.Cut(scanner.Text(), "\t")
+		if !found {
+			continue
+		}
+
+		n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+		if err != nil {
+			continue
+		}
+
+		normalised := mutate(word)
+		if filter(normalised) {
+			frequencies[normalised] += n
+		}
+	}
+
+	return frequencies, scanner.Err()
+}
@@ -61,3 +61,39 @@ func createTempFile(t *testing.T, contents string) (*os.File, func()) {
 		_ = os.Remove(file.Name())
 	}
 }
+
+func TestParseLineSeperatedDictionaryOfSeveralLengths(t *testing.T) {
+	var contents = `cat
+tram
+crate
+charts
+streams
+drifted`
+
+	file, tidyFn := createTempFile(t, contents)
+
+	defer tidyFn()
+
+	wordSelect := ParseLineSeperatedDictionary(file.Name())
+	words, err := wordSelect(NormaliseWord, WordleCandidateOfLength(4, 6))
+	require.NoError(t, err)
+	assert.EqualValues(t, []string{"tram", "charts"}, words)
+}
+
+func TestParseFrequencies(t *testing.T) {
+	var contents = `the	23135851162
+April	1000
+april	200
+crane	3011
+cafés	99
+crane count
+slate	12.5`
+
+	file, tidyFn := createTempFile(t, contents)
+
+	defer tidyFn()
+
+	frequencies, err := ParseFrequencies(file.Name(), NormaliseWord, WordleCandidate)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"april": 1200, "crane": 3011, "slate": 12.5}, frequencies)
+}
@@ -23,14 +23,14 @@ func (w *WordSources) LoadWords(ctx context.Context, log *logr.Logger, mutate Mu
 	go func() {
 		defer wg.Done()
 		englishWordsDictionary := ParseLineSeperatedDictionary(w.EnglishWordFile)
-		producer.Produce(englishWordsDictionary, filter, mutate)
+		producer.Produce(w.TagOf(w.EnglishWordFile), englishWordsDictionary, filter, mutate)
 	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		localWordsList := ParseLineSeperatedDictionary(w.LocalWordFiles[0])
-		producer.Produce(localWordsList, filter, mutate)
+		producer.Produce(w.TagOf(w.LocalWordFiles[0]), localWordsList, filter, mutate)
 	}()
 
 	for _, wordFile := range w.WordSetFiles {
@@ -38,7 +38,7 @@ func (w *WordSources) LoadWords(ctx context.Context, log *logr.Logger, mutate Mu
 		fp := wordFile
 		go func() {
 			defer wg.Done()
-			producer.Produce(ParseWordsetDictionary(fp), filter, mutate)
+			producer.Produce(w.TagOf(fp), ParseWordsetDictionary(fp), filter, mutate)
 		}()
 	}
 
@@ -48,14 +48,31 @@ func (w *WordSources) LoadWords(ctx context.Context, log *logr.Logger, mutate Mu
 	words := consumer.ListWords()
 
 	return &Words{
-		Size:  len(words),
-		Words: words,
+		Size:    len(words),
+		Words:   words,
+		Answers: consumer.ListAnswers(),
 	}, nil
 }
 
+// Words holds every word that is a valid guess, Answers are the subset of them that can be the wordle
 type Words struct {
-	Size  int
-	Words []string
+	Size    int
+	Words   []string
+	Answers []string
+}
+
+type SourceTag int
+
+const (
+	AnswerSource      SourceTag = iota // the source's words are valid guesses and can be the wordle
+	GuessesOnlySource                  // the source's words are valid guesses but too obscure to be the wordle
+)
+
+func (t SourceTag) String() string {
+	if t == GuessesOnlySource {
+		return "guesses-only"
+	}
+	return "answers"
 }
 
 func NewWordSources(config Config) (*WordSources, error) {
@@ -89,6 +106,14 @@ func NewWordSources(config Config) (*WordSources, error) {
 	})
 
 	wordSources.WordSetFiles = wsf
+
+	wordSources.Tags = map[string]SourceTag{
+		wordSources.EnglishWordFile: GuessesOnlySource,
+		ld:                          GuessesOnlySource,
+	}
+	for _, f := range wsf {
+		wordSources.Tags[f] = AnswerSource
+	}
 	return &wordSources, nil
 }
 
@@ -96,9 +121,14 @@ type WordSources struct {
 	WordSetFiles    []string
 	EnglishWordFile string
 	LocalWordFiles  []string
+	Tags            map[string]SourceTag // keyed by file, untagged files are treated as answers
 	baseDir         string
 }
 
+func (w WordSources) TagOf(file string) SourceTag {
+	return w.Tags[file]
+}
+
 func (w WordSources) filepath(path string) (string, error) {
 	fullpath := w.baseDir + "/" + path
 	_, err := os.Stat(fullpath)
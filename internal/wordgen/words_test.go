@@ -29,3 +29,53 @@ func TestCompileWordList(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 31941, compiled.Size)
 }
+
+func TestDictionaryConfigDefaultsToFiveLetterWords(t *testing.T) {
+	t.Setenv(DictionaryBaseDirKey, ".")
+
+	config, err := NewDictionaryConfig(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, config.WordLengths)
+
+	t.Setenv("WORD_LENGTHS", "4,6,7")
+
+	config, err = NewDictionaryConfig(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 6, 7}, config.WordLengths)
+}
+
+func TestLoadWordsSeparatesAnswersFromGuesses(t *testing.T) {
+
+	ctx := context.TODO()
+
+	log, err := wordle.NewProductionLogger("TestLoadWordsSeparatesAnswersFromGuesses")
+	require.NoError(t, err)
+
+	englishWords, tidyEnglish := createTempFile(t, "aahed\ncrwth")
+	defer tidyEnglish()
+
+	localWords, tidyLocal := createTempFile(t, "xylyl")
+	defer tidyLocal()
+
+	wordset, tidyWordset := createTempFile(t, `{"crane": {}, "slate": {}}`)
+	defer tidyWordset()
+
+	wordSource := WordSources{
+		EnglishWordFile: englishWords.Name(),
+		LocalWordFiles:  []string{localWords.Name()},
+		WordSetFiles:    []string{wordset.Name()},
+		Tags: map[string]SourceTag{
+			englishWords.Name(): GuessesOnlySource,
+			localWords.Name():   GuessesOnlySource,
+		},
+	}
+
+	for i := 0; i < 20; i++ {
+		compiled, err := wordSource.LoadWords(ctx, log, NormaliseWord, WordleCandidate)
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, compiled.Size)
+		assert.ElementsMatch(t, []string{"aahed", "crwth", "xylyl", "crane", "slate"}, compiled.Words)
+		assert.ElementsMatch(t, []string{"crane", "slate"}, compiled.Answers)
+	}
+}
@@ -0,0 +1,354 @@
+/*
+Package server answers POST /wordle/solve over HTTP. The request lists the guesses made so far with the feedback each
+one got, in any of the styles db.ParseFeedback reads
+
+	{"guesses": [{"word": "crane", "feedback": "BBYBG"}], "limit": 20, "suggestions": 5}
+
+limit caps how many candidates are returned (default 20, at most 1000) and suggestions how many next guesses are
+recommended (default 5, at most 20, 0 for none). The response counts every candidate and lists the most likely first
+
+	{"count": 42, "candidates": ["those", ...], "suggestions": [{"word": "doubt", "expectedBits": 4.1, ...}], "solved": false}
+
+Invalid input is a 4xx with {"error": "...", "field": "guesses[0].feedback"}, the full JSON schema is in
+solve.schema.json alongside this package.
+*/
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+)
+
+const (
+	SolvePath          = "/wordle/solve"
+	DefaultTimeout     = 10 * time.Second
+	DefaultLimit       = 20
+	MaxLimit           = 1000
+	DefaultSuggestions = 5
+	MaxSuggestions     = 20
+	maxBodyBytes       = 64 << 10
+
+	statusClientClosedRequest = 499 // nginx's status for a client that went away before it was answered
+)
+
+type SolveRequest struct {
+	Guesses     []GuessFeedback `json:"guesses"`
+	Limit       *int            `json:"limit,omitempty"`
+	Suggestions *int            `json:"suggestions,omitempty"`
+}
+
+type GuessFeedback struct {
+	Word     string `json:"word"`
+	Feedback string `json:"feedback"`
+}
+
+type SolveResponse struct {
+	Count       int          `json:"count"`
+	Candidates  []string     `json:"candidates"`
+	Suggestions []Suggestion `json:"suggestions"`
+	Solved      bool         `json:"solved"`
+}
+
+type Suggestion struct {
+	Word              string  `json:"word"`
+	ExpectedBits      float64 `json:"expectedBits"`
+	ExpectedRemaining float64 `json:"expectedRemaining"`
+	WorstCase         int     `json:"worstCase"`
+	Candidate         bool    `json:"candidate"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Field string `json:"field,omitempty"` // the part of the request that was invalid
+}
+
+// requestError is an invalid request, reported to the client with its status
+type requestError struct {
+	status int
+	field  string
+	err    error
+}
+
+func (e requestError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(field string, format string, a ...interface{}) error {
+	return requestError{status: http.StatusBadRequest, field: field, err: errors.Errorf(format, a...)}
+}
+
+// Server searches with the engine and, when it has a solver, recommends the next guesses
+type Server struct {
+	log     logr.Logger
+	engine  db.WordSearchEngine
+	solver  *db.Solver
+	timeout time.Duration
+}
+
+func New(log logr.Logger, engine db.WordSearchEngine, solver *db.Solver, timeout time.Duration) *Server {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Server{
+		log:     log,
+		engine:  engine,
+		solver:  solver,
+		timeout: timeout,
+	}
+}
+
+// Handler routes SolvePath to the server, every other path is a 404
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(SolvePath, s)
+	return mux
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		s.writeError(w, requestError{status: http.StatusMethodNotAllowed, err: errors.Errorf("method %s is not allowed, use POST", r.Method)})
+		return
+	}
+
+	request, err := decode(w, r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+
+	type result struct {
+		response *SolveResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := s.Solve(ctx, request)
+		done <- result{response, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		s.writeError(w, s.contextError(ctx.Err()))
+	case res := <-done:
+		if res.err != nil {
+			s.writeError(w, s.contextError(res.err))
+			return
+		}
+		s.write(w, http.StatusOK, res.response)
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request) (*SolveRequest, error) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+			return nil, requestError{
+				status: http.StatusUnsupportedMediaType,
+				err:    errors.Errorf("content type [%s] is not supported, use application/json", contentType),
+			}
+		}
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+
+	var request SolveRequest
+	if err := decoder.Decode(&request); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, requestError{
+				status: http.StatusRequestEntityTooLarge,
+				err:    errors.Errorf("the request body is larger than %d bytes", maxBodyBytes),
+			}
+		}
+		return nil, badRequest("", "the request body is not valid JSON: %v", err)
+	}
+	return &request, nil
+}
+
+// Solve validates the request and finds the words matching every guess, giving up once ctx is done
+func (s *Server) Solve(ctx context.Context, request *SolveRequest) (*SolveResponse, error) {
+	guesses, err := request.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidates(ctx, guesses)
+	if err != nil {
+		return nil, err
+	}
+
+	last := guesses[len(guesses)-1]
+	response := &SolveResponse{
+		Count:       len(candidates),
+		Candidates:  []string{},
+		Suggestions: []Suggestion{},
+		Solved:      db.PatternOf(last.Knowledge()).Solved(last.Length()),
+	}
+
+	limit := valueOr(request.Limit, DefaultLimit)
+	if len(candidates) > limit {
+		response.Candidates = append(response.Candidates, candidates[:limit]...)
+	} else {
+		response.Candidates = append(response.Candidates, candidates...)
+	}
+
+	if n := valueOr(request.Suggestions, DefaultSuggestions); s.solver != nil && n > 0 && !response.Solved {
+		suggestions, err := s.solver.RankCandidates(ctx, candidates, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, suggestion := range suggestions {
+			response.Suggestions = append(response.Suggestions, Suggestion(suggestion))
+		}
+	}
+	return response, nil
+}
+
+// constraintFilter is an engine that can search with every guess at once, like dynamo.SearchEngine
+type constraintFilter interface {
+	Filter(ctx context.Context, c *db.Constraints) ([]string, error)
+}
+
+/*
+candidates combines the guesses into one set of constraints as db.Session does, so an index or an engine that can
+filter with them searches once. Any other engine is searched guess by guess, keeping the words found for all of them.
+*/
+func (s *Server) candidates(ctx context.Context, guesses []db.Wordle) ([]string, error) {
+	constraints := db.NewConstraints(guesses[0].Length())
+	for _, guess := range guesses {
+		constraints.Add(guess)
+	}
+
+	switch engine := s.engine.(type) {
+	case *db.Index:
+		return engine.OrderByLikelihood(engine.Filter(constraints)), ctx.Err()
+	case constraintFilter:
+		return engine.Filter(ctx, constraints)
+	}
+
+	var candidates []string
+	for i, guess := range guesses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := s.engine.Search(guess)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error searching for guess %d [%s]", i+1, guess.Letters())
+		}
+
+		if i == 0 {
+			candidates = result.Items
+		} else {
+			candidates = intersect(result.Items, candidates)
+		}
+	}
+	return candidates, nil
+}
+
+func (r *SolveRequest) validate() ([]db.Wordle, error) {
+	if len(r.Guesses) == 0 {
+		return nil, badRequest("guesses", "at least one guess is required")
+	}
+	if len(r.Guesses) > db.MaxGuesses {
+		return nil, badRequest("guesses", "at most %d guesses are allowed", db.MaxGuesses)
+	}
+	if n := valueOr(r.Limit, DefaultLimit); n < 0 || n > MaxLimit {
+		return nil, badRequest("limit", "limit must be between 0 and %d", MaxLimit)
+	}
+	if n := valueOr(r.Suggestions, DefaultSuggestions); n < 0 || n > MaxSuggestions {
+		return nil, badRequest("suggestions", "suggestions must be between 0 and %d", MaxSuggestions)
+	}
+
+	var guesses []db.Wordle
+	for i, g := range r.Guesses {
+		field := fmt.Sprintf("guesses[%d]", i)
+		word := strings.ToLower(strings.TrimSpace(g.Word))
+		if len(word) == 0 || len(word) > db.MaxWordLength || strings.Trim(word, "abcdefghijklmnopqrstuvwxyz") != "" {
+			return nil, badRequest(field+".word", "word [%s] must be 1 to %d letters from a to z", g.Word, db.MaxWordLength)
+		}
+		if i > 0 && len(word) != guesses[0].Length() {
+			return nil, badRequest(field+".word", "word [%s] must have %d letters like the first guess", g.Word, guesses[0].Length())
+		}
+
+		w, err := db.ParseGuessFeedback(word, g.Feedback)
+		if err != nil {
+			return nil, badRequest(field+".feedback", "%v", err)
+		}
+		if i < len(r.Guesses)-1 && db.PatternOf(w.Knowledge()).Solved(w.Length()) {
+			return nil, badRequest(field+".feedback", "only the last guess can be solved")
+		}
+		guesses = append(guesses, *w)
+	}
+	return guesses, nil
+}
+
+// intersect keeps the words in order that are also in other
+func intersect(words []string, other []string) []string {
+	keep := make(map[string]bool, len(other))
+	for _, w := range other {
+		keep[w] = true
+	}
+
+	var both []string
+	for _, w := range words {
+		if keep[w] {
+			both = append(both, w)
+		}
+	}
+	return both
+}
+
+func valueOr(n *int, fallback int) int {
+	if n == nil {
+		return fallback
+	}
+	return *n
+}
+
+/*
+contextError turns Solve giving up into the response for why it gave up, which can arrive on either side of the
+select in ServeHTTP. Running out of time is a 503, a client that cancelled is not waiting for an answer.
+*/
+func (s *Server) contextError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return requestError{status: http.StatusServiceUnavailable, err: errors.Errorf("the request took longer than %v", s.timeout)}
+	case errors.Is(err, context.Canceled):
+		return requestError{status: statusClientClosedRequest, err: errors.New("the request was cancelled")}
+	}
+	return err
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	var requestErr requestError
+	if errors.As(err, &requestErr) {
+		s.write(w, requestErr.status, ErrorResponse{Error: requestErr.Error(), Field: requestErr.field})
+		return
+	}
+
+	s.log.Error(err, "error solving request")
+	s.write(w, http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+}
+
+func (s *Server) write(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.log.Error(err, "error writing response")
+	}
+}
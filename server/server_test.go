@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServer(t *testing.T) *Server {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	index, err := db.NewIndex(*log, []string{"brick", "crane", "crate", "flock", "plumb", "slate", "trace"})
+	require.NoError(t, err)
+
+	return New(*log, index, db.NewSolver(index, db.EntropyStrategy{}), time.Second)
+}
+
+func post(t *testing.T, handler http.Handler, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, SolvePath, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestSolveReturnsCandidatesAndSuggestions(t *testing.T) {
+	recorder := post(t, testServer(t).Handler(), `{"guesses": [{"word": "brick", "feedback": "BGBYB"}], "limit": 1, "suggestions": 2}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var response SolveResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, []string{"crane"}, response.Candidates)
+	require.Len(t, response.Suggestions, 2)
+	assert.False(t, response.Solved)
+}
+
+func TestSolveIntersectsEveryGuess(t *testing.T) {
+	recorder := post(t, testServer(t).Handler(), `{"guesses": [
+		{"word": "slate", "feedback": "BBYYB"},
+		{"word": "trace", "feedback": "🟨🟩🟩⬛⬛"}
+	]}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response SolveResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 0, response.Count)
+	assert.Equal(t, []string{}, response.Candidates)
+	assert.Equal(t, []Suggestion{}, response.Suggestions)
+}
+
+func TestSolveSolved(t *testing.T) {
+	recorder := post(t, testServer(t).Handler(), `{"guesses": [{"word": "CRATE", "feedback": "22222"}]}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	assert.JSONEq(t, `{"count": 1, "candidates": ["crate"], "suggestions": [], "solved": true}`, recorder.Body.String())
+}
+
+func TestSolveRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		error string
+		field string
+	}{
+		{"not json", `{"guesses": `, "the request body is not valid JSON: unexpected EOF", ""},
+		{"unknown field", `{"guess": []}`, `the request body is not valid JSON: json: unknown field "guess"`, ""},
+		{"no guesses", `{"guesses": []}`, "at least one guess is required", "guesses"},
+		{"too many guesses", `{"guesses": [` + strings.Repeat(`{"word": "slate", "feedback": "BBBBB"},`, 6) + `{"word": "slate", "feedback": "BBBBB"}]}`, "at most 6 guesses are allowed", "guesses"},
+		{"limit", `{"guesses": [{"word": "slate", "feedback": "BBBBB"}], "limit": -1}`, "limit must be between 0 and 1000", "limit"},
+		{"suggestions", `{"guesses": [{"word": "slate", "feedback": "BBBBB"}], "suggestions": 21}`, "suggestions must be between 0 and 20", "suggestions"},
+		{"word", `{"guesses": [{"word": "sl4te", "feedback": "BBBBB"}]}`, "word [sl4te] must be 1 to 20 letters from a to z", "guesses[0].word"},
+		{"lengths", `{"guesses": [{"word": "slate", "feedback": "BBBBB"}, {"word": "brick", "feedback": "BBBBB"}, {"word": "cranes", "feedback": "BBBBBB"}]}`, "word [cranes] must have 5 letters like the first guess", "guesses[2].word"},
+		{"feedback", `{"guesses": [{"word": "slate", "feedback": "BBRBB"}]}`, "unexpected [R] at position 3 in feedback [BBRBB], expected G, Y and B, 2, 1 and 0 or the share emoji", "guesses[0].feedback"},
+		{"feedback length", `{"guesses": [{"word": "slate", "feedback": "BBB"}]}`, "feedback [BBB] has 3 letters but the guess [slate] has 5", "guesses[0].feedback"},
+		{"solved early", `{"guesses": [{"word": "slate", "feedback": "GGGGG"}, {"word": "crate", "feedback": "BBBBB"}]}`, "only the last guess can be solved", "guesses[0].feedback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := post(t, testServer(t).Handler(), tt.body)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, tt.error, response.Error)
+			assert.Equal(t, tt.field, response.Field)
+		})
+	}
+}
+
+func TestSolveRejectsTheWrongRequest(t *testing.T) {
+	handler := testServer(t).Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SolvePath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, http.MethodPost, recorder.Header().Get("Allow"))
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, SolvePath, strings.NewReader("guesses=slate"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+
+	recorder = post(t, handler, `{"guesses": [{"word": "`+strings.Repeat("a", maxBodyBytes)+`"}]}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wordle", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+// engineFunc adapts a function to db.WordSearchEngine
+type engineFunc func(guess db.Wordle) (*db.MatchResult, error)
+
+func (f engineFunc) Search(guess db.Wordle) (*db.MatchResult, error) {
+	return f(guess)
+}
+
+func TestSolveTimesOut(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := engineFunc(func(guess db.Wordle) (*db.MatchResult, error) {
+		<-release
+		return &db.MatchResult{}, nil
+	})
+
+	recorder := post(t, New(*log, slow, nil, 10*time.Millisecond).Handler(), `{"guesses": [{"word": "slate", "feedback": "BBBBB"}]}`)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"error": "the request took longer than 10ms"}`, recorder.Body.String())
+}
+
+func TestSolveHidesEngineErrors(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	broken := engineFunc(func(guess db.Wordle) (*db.MatchResult, error) {
+		return nil, errors.New("the table is missing")
+	})
+
+	recorder := post(t, New(*log, broken, nil, time.Second).Handler(), `{"guesses": [{"word": "slate", "feedback": "BBBBB"}]}`)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.JSONEq(t, `{"error": "internal error"}`, recorder.Body.String())
+}
+
+func TestSolveStopsOnceCancelled(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	var searches int
+	counting := engineFunc(func(guess db.Wordle) (*db.MatchResult, error) {
+		searches++
+		return &db.MatchResult{Items: []string{"crane"}}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := &SolveRequest{Guesses: []GuessFeedback{{Word: "slate", Feedback: "BBYBB"}}}
+	_, err = New(*log, counting, nil, time.Second).Solve(ctx, request)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, searches)
+
+	_, err = testServer(t).Solve(ctx, request)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSolveAgreesAcrossEngines(t *testing.T) {
+	index := testServer(t)
+	engine := New(index.log, db.NewSearchEngine(index.engine.(*db.Index)), index.solver, time.Second)
+
+	request := &SolveRequest{Guesses: []GuessFeedback{{Word: "slate", Feedback: "BBGBG"}, {Word: "brick", Feedback: "BGBYB"}}}
+	expected, err := index.Solve(context.Background(), request)
+	require.NoError(t, err)
+	actual, err := engine.Solve(context.Background(), request)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"crane"}, expected.Candidates)
+	assert.Equal(t, expected, actual)
+}
+
+func TestSolveReportsWhyItGaveUp(t *testing.T) {
+	log, err := wordle.NewProductionLogger(t.Name())
+	require.NoError(t, err)
+
+	tests := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{errors.Wrap(context.DeadlineExceeded, "error reading partition"), http.StatusServiceUnavailable, `{"error": "the request took longer than 1s"}`},
+		{context.Canceled, statusClientClosedRequest, `{"error": "the request was cancelled"}`},
+	}
+	for _, tt := range tests {
+		givesUp := engineFunc(func(guess db.Wordle) (*db.MatchResult, error) {
+			return nil, tt.err
+		})
+
+		recorder := post(t, New(*log, givesUp, nil, time.Second).Handler(), `{"guesses": [{"word": "slate", "feedback": "BBBBB"}]}`)
+		assert.Equal(t, tt.status, recorder.Code, tt.err.Error())
+		assert.JSONEq(t, tt.body, recorder.Body.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodPost, SolvePath, strings.NewReader(`{"guesses": [{"word": "slate", "feedback": "BBBBB"}]}`)).WithContext(ctx)
+	recorder := httptest.NewRecorder()
+	testServer(t).Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, statusClientClosedRequest, recorder.Code, "a cancelled client did not run out of time")
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/go-logr/logr"
 	"github.com/howzat/wordle"
 	"github.com/howzat/wordle/internal/wordgen"
 	"github.com/pkg/errors"
@@ -33,6 +35,7 @@ func main() {
 	log.Info("started ingestion",
 		"commitId", CommitID,
 		"baseDir", config.BaseDir,
+		"wordLengths", config.WordLengths,
 	)
 
 	dictionaryConfig, err := wordgen.NewDictionaryConfig(ctx)
@@ -41,24 +44,66 @@ func main() {
 	wordSource, err := wordgen.NewWordSources(dictionaryConfig)
 	failOnErr(err)
 
-	compiled, compileErr := wordSource.LoadWords(ctx, log, wordgen.LowercaseWord, wordgen.WordleCandidate)
+	compiled, compileErr := wordSource.LoadWords(ctx, log, wordgen.LowercaseWord, wordgen.WordleCandidateOfLength(config.WordLengths...))
 
 	log.Info("complete", "ingested", compiled.Size, "error", compileErr)
 
+	uniqueWords := unique(compiled.Words)
+	uniqueAnswers := unique(compiled.Answers)
+
+	log.Info("optimised", "unique", len(uniqueWords), "answers", len(uniqueAnswers))
+
+	writeWords(log, "cmd/search/dictionary.txt", uniqueWords)
+	writeWords(log, "cmd/search/answers.txt", uniqueAnswers)
+
+	if config.FrequencyFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(config.FrequencyFile, wordgen.LowercaseWord, wordgen.WordleCandidateOfLength(config.WordLengths...))
+		failOnErr(err)
+
+		writeFrequencies(log, "cmd/search/frequencies.tsv", uniqueWords, frequencies)
+	}
+}
+
+// writeFrequencies keeps the counts of the dictionary words only, words without a count are left for the index to smooth
+func writeFrequencies(log *logr.Logger, filepath string, words []string, frequencies map[string]float64) {
+	frequencyFile, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	failOnErr(err)
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(frequencyFile)
+
+	sort.Sort(sort.StringSlice(words))
+
+	var builder strings.Builder
+	var written int
+	for _, word := range words {
+		if count, ok := frequencies[word]; ok {
+			builder.WriteString(word + "\t" + strconv.FormatFloat(count, 'f', -1, 64) + "\n")
+			written++
+		}
+	}
+
+	log.Info(fmt.Sprintf("About to write %v frequencies to %v", written, filepath))
+
+	_, err = frequencyFile.WriteString(builder.String())
+	failOnErr(err)
+}
+
+func unique(all []string) []string {
 	words := map[string]bool{}
 	var uniqueWords []string
-	for _, word := range compiled.Words {
-		if words != nil {
-			if _, present := words[word]; !present {
-				words[word] = true
-				uniqueWords = append(uniqueWords, word)
-			}
+	for _, word := range all {
+		if _, present := words[word]; !present {
+			words[word] = true
+			uniqueWords = append(uniqueWords, word)
 		}
 	}
+	return uniqueWords
+}
 
-	log.Info("optimised", "unique", len(uniqueWords))
-
-	dictionaryFile, err := os.OpenFile("cmd/search/dictionary.txt", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+func writeWords(log *logr.Logger, filepath string, words []string) {
+	dictionaryFile, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
 	failOnErr(err)
 
 	defer func(f *os.File) {
@@ -71,12 +116,12 @@ func main() {
 	_, err = dictionaryFile.Seek(0, 0)
 	failOnErr(err)
 
-	sort.Sort(sort.StringSlice(uniqueWords))
+	sort.Sort(sort.StringSlice(words))
 
-	log.Info(fmt.Sprintf("About to write %v lines", len(uniqueWords)))
+	log.Info(fmt.Sprintf("About to write %v lines to %v", len(words), filepath))
 
 	var builder strings.Builder
-	for _, word := range uniqueWords {
+	for _, word := range words {
 		builder.WriteString(word + "\n")
 	}
 
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/db/dynamo"
+	"github.com/howzat/wordle/internal/wordgen"
+)
+
+var CommitID string
+
+func main() {
+
+	endpoint := flag.String("endpoint", os.Getenv("DYNAMO_ENDPOINT"), "DynamoDB endpoint, e.g. http://localhost:8000 for DynamoDB Local, empty for AWS")
+	region := flag.String("region", os.Getenv("AWS_REGION"), "AWS region of the table")
+	table := flag.String("table", dynamo.DefaultTable, "the table created from schema/words.json")
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of possible answers")
+	guessesFile := flag.String("guesses", "", "line separated list of additional allowed guesses")
+	frequenciesFile := flag.String("frequencies", "", "tab separated word counts built by tools/dictionary, used to order candidates")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("admin-load-dynamo")
+	failOnErr(err)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.NoFilter())
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	if *frequenciesFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(*frequenciesFile, wordgen.NormaliseWord, wordgen.NoFilter())
+		failOnErr(err)
+		index.SetPriors(frequencies)
+	}
+
+	client, err := dynamo.NewClient(context.Background(), *region, *endpoint)
+	failOnErr(err)
+
+	start := time.Now()
+	written, err := dynamo.Load(context.Background(), client, *table, index)
+	failOnErr(err)
+
+	log.Info("loaded words",
+		"commitId", CommitID,
+		"table", *table,
+		"endpoint", *endpoint,
+		"items", written,
+		"took", time.Since(start).String(),
+	)
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
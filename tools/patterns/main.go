@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/wordgen"
+)
+
+var CommitID string
+
+func main() {
+
+	length := flag.Int("length", db.DefaultWordLength, fmt.Sprintf("word length, at most %d", db.MaxMatrixLength))
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of possible answers")
+	guessesFile := flag.String("guesses", "", "line separated list of additional allowed guesses")
+	out := flag.String("out", "patterns.wpm", "where to write the pattern matrix")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("admin-build-pattern-matrix")
+	failOnErr(err)
+
+	log.Info("started building pattern matrix",
+		"commitId", CommitID,
+		"length", *length,
+		"answers", *answersFile,
+		"guesses", *guessesFile,
+	)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.Length(*length))
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.Length(*length))
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	started := time.Now()
+	matrix, err := db.BuildPatternMatrix(index.Words(*length), answers)
+	failOnErr(err)
+
+	matrixFile, err := os.OpenFile(*out, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	failOnErr(err)
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(matrixFile)
+
+	written, err := matrix.WriteTo(matrixFile)
+	failOnErr(err)
+
+	log.Info("complete",
+		"guesses", index.SizeOfLength(*length),
+		"answers", len(answers),
+		"bytes", written,
+		"duration", time.Since(started).String(),
+	)
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/wordgen"
+)
+
+var CommitID string
+
+func main() {
+
+	gridFile := flag.String("grid", "", "file holding the shared result grid, defaults to stdin")
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of possible answers")
+	frequenciesFile := flag.String("frequencies", "", "tab separated word counts built by tools/dictionary, used to order the answers")
+	guessesFile := flag.String("guesses", "", "line separated list of additional allowed guesses")
+	limit := flag.Int("limit", 20, "how many answers to list, 0 for all of them")
+	examples := flag.Int("examples", 5, "how many guesses to list for each row, 0 for all of them")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("admin-reverse-grid")
+	failOnErr(err)
+
+	input := os.Stdin
+	if *gridFile != "" {
+		input, err = os.Open(*gridFile)
+		failOnErr(err)
+
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(input)
+	}
+
+	text, err := io.ReadAll(input)
+	failOnErr(err)
+
+	grid, err := db.ParseFeedbackGrid(string(text))
+	failOnErr(err)
+
+	length := len(grid[0])
+	log.Info("started reversing grid",
+		"commitId", CommitID,
+		"rows", len(grid),
+		"length", length,
+		"answers", *answersFile,
+		"guesses", *guessesFile,
+	)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.Length(length))
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.Length(length))
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	if *frequenciesFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(*frequenciesFile, wordgen.NormaliseWord, wordgen.Length(length))
+		failOnErr(err)
+		index.SetPriors(frequencies)
+	}
+
+	matches, err := index.ReverseGrid(grid)
+	failOnErr(err)
+
+	log.Info("complete", "matches", len(matches))
+
+	fmt.Printf("%d answers match the %d row grid\n", len(matches), len(grid))
+	if *limit > 0 && len(matches) > *limit {
+		matches = matches[:*limit]
+	}
+
+	for _, m := range matches {
+		fmt.Printf("\n%s\n", m.Answer)
+		for r, guesses := range m.Rows {
+			fmt.Printf("  %s %s\n", db.FormatFeedback(grid[r], db.EmojiFeedback), summarise(guesses, *examples))
+		}
+	}
+}
+
+func summarise(guesses []string, examples int) string {
+	if examples <= 0 || len(guesses) <= examples {
+		return strings.Join(guesses, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(guesses[:examples], ", "), len(guesses)-examples)
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/wordgen"
+)
+
+var CommitID string
+
+func main() {
+
+	opening := flag.String("opening", "crane", "the first guess of every game")
+	strategyName := flag.String("strategy", db.EntropyStrategyName, fmt.Sprintf("how to choose each next guess, one of %v", db.StrategyNames()))
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of answers to play against")
+	patternsFile := flag.String("patterns", "", "pattern matrix built by tools/patterns from the same answers and guesses")
+	frequenciesFile := flag.String("frequencies", "", "tab separated word counts built by tools/dictionary, used to weight the answers")
+	guessesFile := flag.String("guesses", "", "line separated list of additional allowed guesses")
+	hardMode := flag.Bool("hard", false, "only make guesses that are allowed in hard mode")
+	worst := flag.Int("worst", 10, "how many of the games needing the most guesses to report")
+	format := flag.String("format", "json", "report format, json or text")
+	out := flag.String("out", "", "where to write the report, defaults to stdout")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("admin-simulate-solver")
+	failOnErr(err)
+
+	log.Info("started simulation",
+		"commitId", CommitID,
+		"opening", *opening,
+		"strategy", *strategyName,
+		"hardMode", *hardMode,
+		"answers", *answersFile,
+		"guesses", *guessesFile,
+		"frequencies", *frequenciesFile,
+	)
+
+	strategy, err := db.StrategyNamed(*strategyName)
+	failOnErr(err)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.Length(len(*opening)))
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.Length(len(*opening)))
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	if *frequenciesFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(*frequenciesFile, wordgen.NormaliseWord, wordgen.Length(len(*opening)))
+		failOnErr(err)
+		index.SetPriors(frequencies)
+	}
+
+	solver := db.NewSolver(index, strategy)
+	if *patternsFile != "" {
+		matrix, err := db.OpenPatternMatrix(*patternsFile, index.Words(len(*opening)), answers)
+		failOnErr(err)
+		defer func() {
+			_ = matrix.Close()
+		}()
+		solver.UsePatternMatrix(matrix)
+	}
+
+	report, err := db.NewSimulator(solver, *opening, *hardMode).Run(answers, *worst)
+	failOnErr(err)
+
+	log.Info("complete",
+		"meanGuesses", report.MeanGuesses,
+		"failures", report.Failures,
+		"duration", report.Duration.String(),
+	)
+
+	output := os.Stdout
+	if *out != "" {
+		output, err = os.OpenFile(*out, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		failOnErr(err)
+
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(output)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		failOnErr(encoder.Encode(report))
+	case "text":
+		_, err = fmt.Fprint(output, report.String())
+		failOnErr(err)
+	default:
+		failOnErr(fmt.Errorf("unknown format [%v], expected json or text", *format))
+	}
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/howzat/wordle"
+	"github.com/howzat/wordle/db"
+	"github.com/howzat/wordle/internal/wordgen"
+)
+
+var CommitID string
+
+func main() {
+
+	opening := flag.String("opening", "crane", "the first guess of every game")
+	strategyName := flag.String("strategy", db.EntropyStrategyName, fmt.Sprintf("how to choose each next guess, one of %v", db.StrategyNames()))
+	answersFile := flag.String("answers", "cmd/search/dictionary.txt", "line separated list of possible answers")
+	patternsFile := flag.String("patterns", "", "pattern matrix built by tools/patterns from the same answers and guesses")
+	frequenciesFile := flag.String("frequencies", "", "tab separated word counts built by tools/dictionary, used to weight the answers")
+	guessesFile := flag.String("guesses", "", "line separated list of allowed guesses, when empty each next guess is one of the remaining answers")
+	out := flag.String("out", "", "where to write the tree, defaults to tree-<opening>-<strategy>.txt")
+	flag.Parse()
+
+	log, err := wordle.NewProductionLogger("admin-build-solve-tree")
+	failOnErr(err)
+
+	log.Info("started building tree",
+		"commitId", CommitID,
+		"opening", *opening,
+		"strategy", *strategyName,
+		"answers", *answersFile,
+		"guesses", *guessesFile,
+		"frequencies", *frequenciesFile,
+	)
+
+	strategy, err := db.StrategyNamed(*strategyName)
+	failOnErr(err)
+
+	answers, err := wordgen.ParseLineSeperatedDictionary(*answersFile)(wordgen.NormaliseWord, wordgen.Length(len(*opening)))
+	failOnErr(err)
+
+	var guesses []string
+	if *guessesFile != "" {
+		guesses, err = wordgen.ParseLineSeperatedDictionary(*guessesFile)(wordgen.NormaliseWord, wordgen.Length(len(*opening)))
+		failOnErr(err)
+	}
+
+	index, err := db.NewIndexWithAnswers(*log, guesses, answers)
+	failOnErr(err)
+
+	if *frequenciesFile != "" {
+		frequencies, err := wordgen.ParseFrequencies(*frequenciesFile, wordgen.NormaliseWord, wordgen.Length(len(*opening)))
+		failOnErr(err)
+		index.SetPriors(frequencies)
+	}
+
+	solver := db.NewSolver(index, strategy)
+	if *patternsFile != "" {
+		matrix, err := db.OpenPatternMatrix(*patternsFile, index.Words(len(*opening)), answers)
+		failOnErr(err)
+		defer func() {
+			_ = matrix.Close()
+		}()
+		solver.UsePatternMatrix(matrix)
+	}
+
+	started := time.Now()
+	tree, err := db.BuildDecisionTree(solver, *opening, answers, guesses)
+	failOnErr(err)
+
+	log.Info("complete",
+		"nodes", tree.Size(),
+		"maxDepth", tree.MaxDepth,
+		"averageGuesses", tree.AverageGuesses,
+		"duration", time.Since(started).String(),
+	)
+
+	if *out == "" {
+		*out = fmt.Sprintf("tree-%s-%s.txt", *opening, strategy.Name())
+	}
+
+	treeFile, err := os.OpenFile(*out, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	failOnErr(err)
+
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(treeFile)
+
+	_, err = tree.WriteTo(treeFile)
+	failOnErr(err)
+
+	fmt.Printf("%s: %d answers, max depth %d, average guesses %.4f\n", *out, tree.Answers, tree.MaxDepth, tree.AverageGuesses)
+}
+
+func failOnErr(err error) {
+	if err != nil {
+		panic(err)
+	}
+}